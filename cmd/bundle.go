@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/bundle"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	"github.com/spf13/cobra"
+)
+
+// cmd globals config
+var secretAgentTag string
+var dsOperatorTag string
+var bundleOutput string
+
+var bundlePull = &cobra.Command{
+	Use:   "pull",
+	Short: "Download the release manifests into a bundle",
+	Long: `
+    Download the release manifests into a .tar.gz bundle:
+    * Download the forgeops, secret-agent and ds-operator release manifests
+    * The checksums and signatures published with the releases are included to verify the manifests at install time
+    * Use --tag to specify the forgeops version to download
+    * Use the bundle with --bundle to install or delete without internet access`,
+	Example: `
+    # Pull the "latest" manifests into forgeops-bundle-[TAG].tar.gz, named after the tag of the latest release.
+    forgeops bundle pull
+
+    # Pull a given CDQ version into a given file.
+    forgeops bundle pull --tag 2020.10.28-AlSugoDiNoci --file cdq.tar.gz
+
+    # Install from the bundle in a cluster without internet access.
+    forgeops install quickstart --bundle cdq.tar.gz`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return configureDownloads()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p := profile.Current()
+		forgeopsFiles := []string{p.Spec.QuickstartManifest, release.ChecksumFile, release.SignatureFile}
+		for _, component := range p.Spec.Components {
+			forgeopsFiles = append(forgeopsFiles, component.Manifest)
+		}
+		sort.Strings(forgeopsFiles)
+		releases, err := bundle.Resolve(cmd.Context(),
+			bundle.Release{GHRepo: release.ForgeOpsRepo, Version: tag, Files: forgeopsFiles},
+			bundle.Release{GHRepo: release.SecretAgentRepo, Version: secretAgentTag, Files: []string{"secret-agent.yaml", release.ChecksumFile, release.SignatureFile}},
+			bundle.Release{GHRepo: release.DSOperatorRepo, Version: dsOperatorTag, Files: []string{"ds-operator.yaml", release.ChecksumFile, release.SignatureFile}},
+		)
+		if err != nil {
+			return err
+		}
+		// The bundle is named after the forgeops release it holds
+		if len(bundleOutput) == 0 {
+			bundleOutput = fmt.Sprintf("forgeops-bundle-%s.tar.gz", releases[0].Version)
+		}
+		return bundle.Pull(cmd.Context(), bundleOutput, releases...)
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Manage release manifest bundles for air-gapped installs",
+	Long: `
+    Manage release manifest bundles for air-gapped installs`,
+	Example: `
+    # Pull the "latest" manifests into a bundle.
+    forgeops bundle pull`,
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+func init() {
+	bundlePull.Flags().StringVarP(&tag, "tag", "t", "latest", "Release tag of the forgeops manifests to be pulled")
+	bundlePull.Flags().StringVar(&secretAgentTag, "secret-agent-tag", "latest", "Release tag of the secret-agent manifest to be pulled")
+	bundlePull.Flags().StringVar(&dsOperatorTag, "ds-operator-tag", "latest", "Release tag of the ds-operator manifest to be pulled")
+	bundlePull.Flags().StringVarP(&bundleOutput, "file", "f", "", "Bundle file to be written (default \"forgeops-bundle-[TAG].tar.gz\")")
+	initDownloadFlags(bundlePull.Flags())
+
+	bundleCmd.AddCommand(bundlePull)
+	rootCmd.AddCommand(bundleCmd)
+}
@@ -22,7 +22,7 @@ var cleanCmd = &cobra.Command{
 		clientFactory = factory.NewFactory(cleanFlags)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := clean.Clean(clientFactory, skipUserConfirmation)
+		err := clean.Clean(cmd.Context(), clientFactory, skipUserConfirmation)
 		return err
 	},
 	SilenceUsage:      true,
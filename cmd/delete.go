@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
 	"github.com/ForgeRock/forgeops-cli/pkg/delete"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
@@ -14,6 +16,7 @@ var deleteFlags *genericclioptions.ConfigFlags
 
 var deleteQuickstart = &cobra.Command{
 	Use:     "quickstart",
+	Args:    noArgs,
 	Aliases: []string{"qs"},
 	Short:   "Delete the ForgeRock Cloud Deployment Quickstart (CDQ)",
 	Long: `
@@ -27,7 +30,7 @@ var deleteQuickstart = &cobra.Command{
     # Delete the CDQ from a given namespace.
     forgeops delete quickstart --namespace mynamespace`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := delete.Quickstart(clientFactory, "ForgeRock/forgeops", tag, skipUserConfirmation)
+		err := delete.Quickstart(cmd.Context(), clientFactory, release.ForgeOpsRepo, tag, skipUserConfirmation)
 		return err
 	},
 	SilenceUsage:      true,
@@ -36,6 +39,7 @@ var deleteQuickstart = &cobra.Command{
 
 var deleteSecretAgent = &cobra.Command{
 	Use:     "secret-agent",
+	Args:    noArgs,
 	Aliases: []string{"sa"},
 	Short:   "Delete the ForgeRock Secret Agent",
 	Long: `
@@ -45,7 +49,7 @@ var deleteSecretAgent = &cobra.Command{
     # Delete the secret-agent from the cluster.
     forgeops delete secret-agent`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := delete.GHResource(clientFactory, "ForgeRock/secret-agent", "secret-agent.yaml", tag, true, skipUserConfirmation)
+		err := delete.GHResource(cmd.Context(), clientFactory, release.SecretAgentRepo, "secret-agent.yaml", tag, true, skipUserConfirmation)
 		return err
 	},
 	SilenceUsage:      true,
@@ -54,6 +58,7 @@ var deleteSecretAgent = &cobra.Command{
 
 var deleteDsOperator = &cobra.Command{
 	Use:     "ds-operator",
+	Args:    noArgs,
 	Aliases: []string{"dso"},
 	Short:   "Delete the ForgeRock DS operator",
 	Long: `
@@ -63,7 +68,7 @@ var deleteDsOperator = &cobra.Command{
     # Delete the ds-operator from the cluster.
     forgeops delete ds-operator`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := delete.GHResource(clientFactory, "ForgeRock/ds-operator", "ds-operator.yaml", tag, true, skipUserConfirmation)
+		err := delete.GHResource(cmd.Context(), clientFactory, release.DSOperatorRepo, "ds-operator.yaml", tag, true, skipUserConfirmation)
 		return err
 	},
 	SilenceUsage:      true,
@@ -71,10 +76,11 @@ var deleteDsOperator = &cobra.Command{
 }
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete",
+	Use:   "delete [component]",
 	Short: "Delete common platform components",
 	Long: `
-    Delete common platform components`,
+    Delete common platform components:
+    * The components of the profile provided with --profile are deleted by name, e.g. forgeops delete idm`,
 	Example: `
     # Delete the CDQ from the "default" namespace.
     forgeops delete quickstart
@@ -85,39 +91,33 @@ var deleteCmd = &cobra.Command{
     # Delete the secret-agent from the cluster.
     forgeops delete secret-agent`,
 	// Configure Client Mgr for all subcommands
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		cmd.Parent().PersistentPreRun(cmd.Parent(), args)
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Root().PersistentPreRun(cmd.Root(), args)
 		clientFactory = factory.NewFactory(deleteFlags)
+		return configureManifestSource()
+	},
+	Args: cobra.MaximumNArgs(1),
+	// Components without a subcommand are looked up in the profile provided at runtime
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		component, err := profile.Current().Component(args[0])
+		if err != nil {
+			return err
+		}
+		return delete.ForgeRockComponent(cmd.Context(), clientFactory, release.ForgeOpsRepo, component.Manifest, tag, skipUserConfirmation)
 	},
 	SilenceUsage:      true,
 	DisableAutoGenTag: true,
 }
 
 func generateFRComponentDeleteCommands() {
-	type componentProperties struct {
-		artifactName string
-		hidden       bool
-		aliases      []string
-	}
-	var componentList = map[string]componentProperties{
-		"base":        {"base.yaml", false, []string{}},
-		"directory":   {"ds.yaml", false, []string{"ds"}},
-		"apps":        {"apps.yaml", false, []string{}},
-		"ui":          {"ui.yaml", false, []string{}},
-		"ds-cts":      {"ds-cts.yaml", true, []string{}},
-		"ds-idrepo":   {"ds-idrepo.yaml", true, []string{}},
-		"am":          {"am.yaml", true, []string{}},
-		"amster":      {"amster.yaml", true, []string{}},
-		"idm":         {"idm.yaml", true, []string{}},
-		"admin-ui":    {"admin-ui.yaml", true, []string{}},
-		"end-user-ui": {"end-user-ui.yaml", true, []string{"enduser-ui"}},
-		"login-ui":    {"login-ui.yaml", true, []string{}},
-		"rcs-agent":   {"rcs-agent.yaml", true, []string{}},
-	}
-	newCmd := func(componentName string, componentProperty componentProperties) *cobra.Command {
+	newCmd := func(componentName string, componentProperty profile.Component) *cobra.Command {
 		return &cobra.Command{
 			Use:     componentName,
-			Aliases: componentProperty.aliases,
+			Args:    noArgs,
+			Aliases: componentProperty.Aliases,
 			Short:   fmt.Sprintf("Delete the ForgeRock %[1]s", componentName),
 			Long: fmt.Sprintf(`
             Delete the ForgeRock Identity Platform %[1]s:
@@ -129,15 +129,20 @@ func generateFRComponentDeleteCommands() {
             # Delete the ForgeRock %[1]q in a given namespace.
             forgeops delete %[1]s --namespace mynamespace`, componentName),
 			RunE: func(cmd *cobra.Command, args []string) error {
-				err := delete.ForgeRockComponent(clientFactory, "ForgeRock/forgeops", componentProperty.artifactName, tag, skipUserConfirmation)
-				return err
+				// The component is looked up in the profile provided at runtime
+				component, err := profile.Current().Component(componentName)
+				if err != nil {
+					return err
+				}
+				return delete.ForgeRockComponent(cmd.Context(), clientFactory, release.ForgeOpsRepo, component.Manifest, tag, skipUserConfirmation)
 			},
-			Hidden:            componentProperty.hidden,
+			Hidden:            componentProperty.Hidden,
 			SilenceUsage:      true,
 			DisableAutoGenTag: true,
 		}
 	}
-	for componentName, componentProperty := range componentList {
+	for _, componentProperty := range profile.Current().Spec.Components {
+		componentName := componentProperty.Name
 		cmd := newCmd(componentName, componentProperty)
 		deleteCmd.AddCommand(cmd)
 		if componentName == "base" {
@@ -149,6 +154,7 @@ func generateFRComponentDeleteCommands() {
 func init() {
 	// Install k8s flags
 	deleteFlags = initK8sFlags(deleteCmd.PersistentFlags())
+	initManifestSourceFlags(deleteCmd.PersistentFlags())
 
 	// Delete command-specific flags
 	deleteCmd.PersistentFlags().StringVarP(&tag, "tag", "t", "latest", "Release tag of the component to be deleted")
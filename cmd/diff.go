@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/diff"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// cmd globals config
+var diffFlags *genericclioptions.ConfigFlags
+
+var diffQuickstart = &cobra.Command{
+	Use:     "quickstart",
+	Args:    noArgs,
+	Aliases: []string{"qs"},
+	Short:   "Diff the ForgeRock Cloud Deployment Quickstart (CDQ) against the cluster",
+	Long: `
+    Diff the ForgeRock Cloud Deployment Quickstart (CDQ) against the cluster:
+    * Diff the components of every tier of the CDQ
+    * Use --tag to specify a different CDQ version to compare`,
+	Example: `
+    # Show what installing a given CDQ version would change in a given namespace.
+    forgeops diff quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return diffResult(diff.Quickstart(cmd.Context(), clientFactory, release.ForgeOpsRepo, tag, fqdn))
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+var diffSecretAgent = &cobra.Command{
+	Use:     "secret-agent",
+	Args:    noArgs,
+	Aliases: []string{"sa"},
+	Short:   "Diff the ForgeRock Secret Agent against the cluster",
+	Long: `
+    Diff the ForgeRock secret-agent against the cluster:
+    * Diff the latest secret-agent manifest
+    * Use --tag to specify a different secret-agent version to compare`,
+	Example: `
+    # Show what installing a given secret-agent version would change.
+    forgeops diff sa --tag v0.2.1`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return diffResult(diff.GHResource(cmd.Context(), clientFactory, release.SecretAgentRepo, "secret-agent.yaml", tag))
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+var diffDsOperator = &cobra.Command{
+	Use:     "ds-operator",
+	Args:    noArgs,
+	Aliases: []string{"dso"},
+	Short:   "Diff the ForgeRock DS operator against the cluster",
+	Long: `
+    Diff the ForgeRock ds-operator against the cluster:
+    * Diff the latest ds-operator manifest
+    * Use --tag to specify a different ds-operator version to compare`,
+	Example: `
+    # Show what installing a given ds-operator version would change.
+    forgeops diff ds-operator --tag v0.0.4`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return diffResult(diff.GHResource(cmd.Context(), clientFactory, release.DSOperatorRepo, "ds-operator.yaml", tag))
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [component]",
+	Short: "Diff release manifests against the live objects in the cluster",
+	Long: `
+    Diff release manifests against the live objects in the cluster:
+    * The manifests are rendered the same way "forgeops install" renders them
+    * Fields populated by the server are ignored
+    * Only the diffs are printed to stdout. Notices are printed to stderr
+    * Exits with 1 when objects would change and with 2 when the diff fails, like kubectl diff
+    * The components of the profile provided with --profile are compared by name, e.g. forgeops diff idm`,
+	Example: `
+    # Show what installing the "latest" base would change.
+    forgeops diff base
+
+    # Show what upgrading the CDQ in a given namespace would change.
+    forgeops diff quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace`,
+	// Configure Client Mgr for all subcommands
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Root().PersistentPreRun(cmd.Root(), args)
+		diff.ConfigureOutput()
+		clientFactory = factory.NewFactory(diffFlags)
+		return diffResult(0, configureManifestSource())
+	},
+	Args: cobra.MaximumNArgs(1),
+	// Components without a subcommand are looked up in the profile provided at runtime
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		component, err := profile.Current().Component(args[0])
+		if err != nil {
+			return diffResult(0, err)
+		}
+		return diffResult(diff.ForgeRockComponent(cmd.Context(), clientFactory, release.ForgeOpsRepo, component.Manifest, tag, fqdn))
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+// diffResult sets the exit code of the diff commands: 1 when objects would change and 2 when the diff fails
+func diffResult(changed int, err error) error {
+	if err != nil {
+		return exitError{code: 2, err: err}
+	}
+	if changed > 0 {
+		return exitError{code: 1}
+	}
+	return nil
+}
+
+func generateFRComponentDiffCommands() {
+	newCmd := func(componentName string, componentProperty profile.Component) *cobra.Command {
+		return &cobra.Command{
+			Use:     componentName,
+			Args:    noArgs,
+			Aliases: componentProperty.Aliases,
+			Short:   fmt.Sprintf("Diff the ForgeRock %[1]s against the cluster", componentName),
+			Long: fmt.Sprintf(`
+            Diff the ForgeRock Identity Platform %[1]s against the cluster:
+            * Diff the ForgeRock Identity Platform %[1]q
+            * Use --tag to specify a different version to compare`, componentName),
+			Example: fmt.Sprintf(`
+            # Diff the ForgeRock %[1]q in the default namespace.
+            forgeops diff %[1]s
+            # Diff the ForgeRock %[1]q in a given namespace.
+            forgeops diff %[1]s --namespace mynamespace`, componentName),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				// The component is looked up in the profile provided at runtime
+				component, err := profile.Current().Component(componentName)
+				if err != nil {
+					return diffResult(0, err)
+				}
+				return diffResult(diff.ForgeRockComponent(cmd.Context(), clientFactory, release.ForgeOpsRepo, component.Manifest, tag, fqdn))
+			},
+			Hidden:            componentProperty.Hidden,
+			SilenceUsage:      true,
+			DisableAutoGenTag: true,
+		}
+	}
+	for _, componentProperty := range profile.Current().Spec.Components {
+		componentName := componentProperty.Name
+		cmd := newCmd(componentName, componentProperty)
+		diffCmd.AddCommand(cmd)
+		if componentName == "base" {
+			cmd.PersistentFlags().StringVar(&fqdn, "fqdn", "", "FQDN used in the deployment. (default \"[NAMESPACE].iam.example.com\")")
+		}
+	}
+}
+
+func init() {
+	// Install k8s flags
+	diffFlags = initK8sFlags(diffCmd.PersistentFlags())
+	initManifestSourceFlags(diffCmd.PersistentFlags())
+
+	// Diff command-specific flags
+	diffCmd.PersistentFlags().StringVarP(&tag, "tag", "t", "latest", "Release tag of the component to be compared")
+	diffCmd.Flags().StringVar(&fqdn, "fqdn", "", "FQDN used in the deployment. (default \"[NAMESPACE].iam.example.com\")")
+	diffQuickstart.PersistentFlags().StringVar(&fqdn, "fqdn", "", "FQDN used in the deployment. (default \"[NAMESPACE].iam.example.com\")")
+	diffCmd.AddCommand(diffQuickstart)
+	diffCmd.AddCommand(diffSecretAgent)
+	diffCmd.AddCommand(diffDsOperator)
+	generateFRComponentDiffCommands()
+	rootCmd.AddCommand(diffCmd)
+}
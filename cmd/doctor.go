@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"context"
-
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
 	"github.com/ForgeRock/forgeops-cli/pkg/doctor"
@@ -17,7 +15,6 @@ var (
 	// cmd globals config
 	kubeConfig    string
 	overrides     = &clientcmd.ConfigOverrides{}
-	ctx           context.Context
 	doctorFlags   *genericclioptions.ConfigFlags
 	operatorFlags *genericclioptions.ConfigFlags
 	allNamespaces bool
@@ -63,8 +60,8 @@ var (
 				return err
 			}
 
-			_, confErr := health.Run(clientFactory, configHealth, true)
-			_, platErr := health.Run(clientFactory, platformHlth, false)
+			_, confErr := health.Run(cmd.Context(), clientFactory, configHealth, true)
+			_, platErr := health.Run(cmd.Context(), clientFactory, platformHlth, false)
 			if confErr != nil && platErr != nil {
 				return errors.Wrap(confErr, platErr.Error())
 
@@ -99,7 +96,7 @@ var (
 			if err != nil {
 				return err
 			}
-			_, err = health.Run(clientFactory, hlth, allNamespaces)
+			_, err = health.Run(cmd.Context(), clientFactory, hlth, allNamespaces)
 			return err
 		},
 	}
@@ -133,8 +130,8 @@ var (
 				return err
 			}
 
-			_, operErr := health.Run(clientFactory, operatorHlth, true)
-			_, platErr := health.Run(clientFactory, platformHlth, false)
+			_, operErr := health.Run(cmd.Context(), clientFactory, operatorHlth, true)
+			_, platErr := health.Run(cmd.Context(), clientFactory, platformHlth, false)
 			if operErr != nil && platErr != nil {
 				return errors.Wrap(operErr, platErr.Error())
 
@@ -149,8 +146,6 @@ var (
 )
 
 func init() {
-	ctx = context.Background()
-
 	// Install k8s flags
 	doctorFlags = initK8sFlags(doctorCmd.PersistentFlags())
 
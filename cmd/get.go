@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"path"
+
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
 	"github.com/ForgeRock/forgeops-cli/pkg/get"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -9,6 +12,7 @@ import (
 
 // cmd globals config
 var getFlags *genericclioptions.ConfigFlags
+var releasesLimit int
 
 var getSecrets = &cobra.Command{
 	Use:     "secrets",
@@ -26,7 +30,7 @@ var getSecrets = &cobra.Command{
     forgeops get secrets -o json -n myns`,
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := get.Secrets(clientFactory)
+		err := get.Secrets(cmd.Context(), clientFactory)
 		return err
 	},
 	SilenceUsage:      true,
@@ -46,13 +50,52 @@ var getURLs = &cobra.Command{
     forgeops get urls`,
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := get.URLs(clientFactory, "forgerock")
+		err := get.URLs(cmd.Context(), clientFactory, "forgerock")
 		return err
 	},
 	SilenceUsage:      true,
 	DisableAutoGenTag: true,
 }
 
+var getReleases = &cobra.Command{
+	Use:       "releases [forgeops|secret-agent|ds-operator]...",
+	Aliases:   []string{"release"},
+	Short:     "Get the releases of the ForgeRock Identity Platform components",
+	ValidArgs: []string{"forgeops", "secret-agent", "ds-operator"},
+	Long: `
+    Get the releases published for the ForgeRock Identity Platform components:
+    * Lists the most recent release tags of forgeops, secret-agent and ds-operator
+    * Includes the publishing date and the manifests published with each release
+    * Use the tags listed with --tag to install a given release
+    * Returns json format or prints them in the console`,
+	Example: `
+    # Get the releases of every component.
+    forgeops get releases
+
+    # Get the last 3 releases of the ds-operator in json format.
+    forgeops get releases ds-operator --limit 3 -o json`,
+	Args: cobra.OnlyValidArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return configureDownloads()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repos := release.Repos
+		if len(args) > 0 {
+			repos = []string{}
+			for _, arg := range args {
+				for _, repo := range release.Repos {
+					if path.Base(repo) == arg {
+						repos = append(repos, repo)
+					}
+				}
+			}
+		}
+		return get.Releases(cmd.Context(), repos, releasesLimit)
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
 var getCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get platform information",
@@ -78,5 +121,9 @@ func init() {
 	getCmd.AddCommand(getSecrets)
 	getCmd.AddCommand(getURLs)
 
+	getReleases.Flags().IntVar(&releasesLimit, "limit", 10, "Number of releases listed per component, newest first")
+	initDownloadFlags(getReleases.Flags())
+	getCmd.AddCommand(getReleases)
+
 	rootCmd.AddCommand(getCmd)
 }
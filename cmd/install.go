@@ -1,26 +1,45 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/diagnose"
 	"github.com/ForgeRock/forgeops-cli/pkg/install"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	"github.com/ForgeRock/forgeops-cli/pkg/sizing"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
 // cmd globals config
 var installFlags *genericclioptions.ConfigFlags
 var fqdn string
+var dryRun string
+var installOptions install.Options
+var size string
+var sizeFile string
+var customPath string
+var customComponent string
+var customPathOptions k8s.PathOptions
+var waitTimeouts map[string]string
 
 var quickstart = &cobra.Command{
 	Use:     "quickstart",
+	Args:    noArgs,
 	Aliases: []string{"qs"},
 	Short:   "Install the ForgeRock Cloud Deployment Quickstart (CDQ)",
 	Long: `
     Install the ForgeRock Cloud Deployment Quickstart (CDQ):
     * Install the latest quickstart manifest
-    * Use --tag to specify a different CDQ version to install`,
+    * Use --tag to specify a different CDQ version to install
+    * The tiers are installed in order, waiting for each tier as described in the deployment profile
+    * Use --resume to continue an install that didn't complete from its first incomplete step
+    * Use --timeout to bound all the waits and --wait-timeout to replace the timeouts of the wait stages`,
 	Example: `
       # Install the "latest" CDQ in the "default" namespace.
       forgeops install quickstart
@@ -32,9 +51,15 @@ var quickstart = &cobra.Command{
       forgeops install quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace
       
       # Install the CDQ with a custom FQDN.
-      forgeops install quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace --fqdn demo.customdomain.com`,
+      forgeops install quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace --fqdn demo.customdomain.com
+
+      # Continue an install that didn't complete, e.g. after a timeout.
+      forgeops install quickstart --namespace mynamespace --resume
+
+      # Give DS and AM more time on slow storage classes, within 45 minutes overall.
+      forgeops install quickstart --timeout 45m --wait-timeout ds=20m,am=15m`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := install.Quickstart(clientFactory, "ForgeRock/forgeops", tag, fqdn)
+		err := install.Quickstart(cmd.Context(), clientFactory, release.ForgeOpsRepo, tag, fqdn, installOptions)
 		return err
 	},
 	SilenceUsage:      true,
@@ -43,6 +68,7 @@ var quickstart = &cobra.Command{
 
 var secretAgent = &cobra.Command{
 	Use:     "secret-agent",
+	Args:    noArgs,
 	Aliases: []string{"sa"},
 	Short:   "Install the ForgeRock Secret Agent",
 	Long: `
@@ -56,7 +82,7 @@ var secretAgent = &cobra.Command{
       # Install a specific version of the secret-agent.
       forgeops install sa --tag v0.2.1`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := install.GHResource(clientFactory, "ForgeRock/secret-agent", "secret-agent.yaml", tag)
+		err := install.GHResource(cmd.Context(), clientFactory, release.SecretAgentRepo, "secret-agent.yaml", tag, installOptions)
 		return err
 	},
 	SilenceUsage:      true,
@@ -65,6 +91,7 @@ var secretAgent = &cobra.Command{
 
 var dsOperator = &cobra.Command{
 	Use:     "ds-operator",
+	Args:    noArgs,
 	Aliases: []string{"dso"},
 	Short:   "Install the ForgeRock DS operator",
 	Long: `
@@ -78,18 +105,56 @@ var dsOperator = &cobra.Command{
       # Install a specific version of the ds-operator.
       forgeops install ds-operator --tag v0.0.4`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := install.GHResource(clientFactory, "ForgeRock/ds-operator", "ds-operator.yaml", tag)
+		err := install.GHResource(cmd.Context(), clientFactory, release.DSOperatorRepo, "ds-operator.yaml", tag, installOptions)
 		return err
 	},
 	SilenceUsage:      true,
 	DisableAutoGenTag: true,
 }
 
+var custom = &cobra.Command{
+	Use:   "custom",
+	Args:  noArgs,
+	Short: "Install manifests or a kustomization from a local path",
+	Long: `
+    Install manifests or a kustomization from a local path, e.g. site-specific overlays of the forgeops kustomize bases:
+    * Use -f to install the manifests in a file or directory. Add --recursive to process subdirectories
+    * Use -k to build and install a kustomization directory
+    * The objects are labeled with the component given by --component, which defaults to the name of the path
+    * --component is required when the name of the path is a component of the platform, e.g. base`,
+	Example: `
+      # Install a kustomize overlay in a given namespace.
+      forgeops install custom -k ./overlays/prod --namespace prod
+
+      # Install every manifest in a directory tree.
+      forgeops install custom -f ./manifests --recursive
+
+      # Print the objects of an overlay without creating them.
+      forgeops install custom -k ./overlays/prod --dry-run`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		kustomizeDir, _ := cmd.Flags().GetString("kustomize")
+		if (len(customPath) == 0) == (len(kustomizeDir) == 0) {
+			return errors.New("exactly one of --filename or --kustomize must be provided")
+		}
+		if len(kustomizeDir) > 0 {
+			customPath = kustomizeDir
+			customPathOptions.Kustomize = true
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return install.Custom(cmd.Context(), clientFactory, customPath, customComponent, customPathOptions, installOptions)
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
 var installCmd = &cobra.Command{
-	Use:   "install",
+	Use:   "install [component]",
 	Short: "Install common platform components",
 	Long: `
-	Install common platform components`,
+	Install common platform components:
+	* The components of the profile provided with --profile are installed by name, e.g. forgeops install idm`,
 	Example: `
     # Install the "latest" ds-operator.
     forgeops install ds-operator
@@ -101,41 +166,62 @@ var installCmd = &cobra.Command{
     forgeops install quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace
     
     # Install the CDQ with a custom FQDN.
-    forgeops install quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace --fqdn demo.customdomain.com`,
+    forgeops install quickstart --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace --fqdn demo.customdomain.com
+
+    # Print the objects that would be created without creating them.
+    forgeops install base --fqdn demo.customdomain.com --dry-run=client
+
+    # Install the CDQ sized for a small production cluster.
+    forgeops install quickstart --size small --fqdn demo.customdomain.com
+
+    # Install the CDQ labeling every object and pod for cost allocation.
+    forgeops install quickstart --labels team=iam,env=stage --propagate-pod-metadata
+
+    # Install the CDQ pulling the images from an internal registry.
+    forgeops install quickstart --image-registry mirror.corp/forgerock --set-image am=mirror.corp/forgerock/am:custom`,
 	// Configure Client Mgr for all subcommands
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		cmd.Parent().PersistentPreRun(cmd.Parent(), args)
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Root().PersistentPreRun(cmd.Root(), args)
 		clientFactory = factory.NewFactory(installFlags)
+		strategy, err := install.ParseDryRunStrategy(dryRun)
+		if err != nil {
+			return err
+		}
+		installOptions.DryRun = strategy
+		install.ConfigureDryRunOutput(strategy)
+		if err := install.ValidateMetadata(installOptions.Labels, installOptions.Annotations); err != nil {
+			return err
+		}
+		if err := configureSize(&installOptions); err != nil {
+			return err
+		}
+		if err := configureWaitTimeouts(&installOptions); err != nil {
+			return err
+		}
+		return configureManifestSource()
+	},
+	Args: cobra.MaximumNArgs(1),
+	// Components without a subcommand are looked up in the profile provided at runtime
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+		component, err := profile.Current().Component(args[0])
+		if err != nil {
+			return err
+		}
+		return install.ForgeRockComponent(cmd.Context(), clientFactory, release.ForgeOpsRepo, component.Manifest, tag, fqdn, installOptions)
 	},
 	SilenceUsage:      true,
 	DisableAutoGenTag: true,
 }
 
 func generateFRComponentInstallCommands() {
-	type componentProperties struct {
-		artifactName string
-		hidden       bool
-		aliases      []string
-	}
-	var componentList = map[string]componentProperties{
-		"base":        {"base.yaml", false, []string{}},
-		"directory":   {"ds.yaml", false, []string{"ds"}},
-		"apps":        {"apps.yaml", false, []string{}},
-		"ui":          {"ui.yaml", false, []string{}},
-		"ds-cts":      {"ds-cts.yaml", true, []string{}},
-		"ds-idrepo":   {"ds-idrepo.yaml", true, []string{}},
-		"am":          {"am.yaml", true, []string{}},
-		"amster":      {"amster.yaml", true, []string{}},
-		"idm":         {"idm.yaml", true, []string{}},
-		"admin-ui":    {"admin-ui.yaml", true, []string{}},
-		"end-user-ui": {"end-user-ui.yaml", true, []string{"enduser-ui"}},
-		"login-ui":    {"login-ui.yaml", true, []string{}},
-		"rcs-agent":   {"rcs-agent.yaml", true, []string{}},
-	}
-	newCmd := func(componentName string, componentProperty componentProperties) *cobra.Command {
+	newCmd := func(componentName string, componentProperty profile.Component) *cobra.Command {
 		return &cobra.Command{
 			Use:     componentName,
-			Aliases: componentProperty.aliases,
+			Args:    noArgs,
+			Aliases: componentProperty.Aliases,
 			Short:   fmt.Sprintf("Install the ForgeRock %[1]s", componentName),
 			Long: fmt.Sprintf(`
             Install the ForgeRock Identity Platform %[1]s:
@@ -147,15 +233,20 @@ func generateFRComponentInstallCommands() {
             # Install the ForgeRock %[1]q in a given namespace.
             forgeops install %[1]s --namespace mynamespace`, componentName),
 			RunE: func(cmd *cobra.Command, args []string) error {
-				err := install.ForgeRockComponent(clientFactory, "ForgeRock/forgeops", componentProperty.artifactName, tag, fqdn)
-				return err
+				// The component is looked up in the profile provided at runtime
+				component, err := profile.Current().Component(componentName)
+				if err != nil {
+					return err
+				}
+				return install.ForgeRockComponent(cmd.Context(), clientFactory, release.ForgeOpsRepo, component.Manifest, tag, fqdn, installOptions)
 			},
-			Hidden:            componentProperty.hidden,
+			Hidden:            componentProperty.Hidden,
 			SilenceUsage:      true,
 			DisableAutoGenTag: true,
 		}
 	}
-	for componentName, componentProperty := range componentList {
+	for _, componentProperty := range profile.Current().Spec.Components {
+		componentName := componentProperty.Name
 		cmd := newCmd(componentName, componentProperty)
 		installCmd.AddCommand(cmd)
 		if componentName == "base" {
@@ -164,14 +255,97 @@ func generateFRComponentInstallCommands() {
 	}
 }
 
+// noArgs rejects leftover arguments. The value of a boolean or optional flag given after a space,
+// e.g. --dry-run server, is parsed as an argument instead of the value of the flag
+func noArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected argument %q for %q. Values of boolean and optional flags must be given with =, e.g. --flag=value", args[0], cmd.CommandPath())
+	}
+	return nil
+}
+
+func initSizeFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&size, "size", "", "(options: cdk|mini|small|medium|large) Set the replicas, resources and storage of the workloads. The sizes of the release manifests are used when empty")
+	flags.StringVar(&sizeFile, "size-file", "", "YAML file with additional sizes or replacements of the default sizes")
+}
+
+// configureSize resolves the size selected with --size
+func configureSize(opts *install.Options) error {
+	if len(size) == 0 {
+		return nil
+	}
+	sizes, err := sizing.Load(sizeFile)
+	if err != nil {
+		return err
+	}
+	s, err := sizes.Size(size)
+	if err != nil {
+		return err
+	}
+	opts.Size = &s
+	return nil
+}
+
+func initMetadataFlags(flags *pflag.FlagSet, opts *install.Options) {
+	flags.StringToStringVar(&opts.Labels, "labels", nil, "Labels added to every object, e.g. team=iam,env=stage")
+	flags.StringToStringVar(&opts.Annotations, "annotations", nil, "Annotations added to every object, e.g. cost-center=1234")
+	flags.BoolVar(&opts.PropagateMetadata, "propagate-pod-metadata", false, "Also add the labels and annotations to the pod templates of the workloads")
+}
+
+func initDiagnosticsFlags(flags *pflag.FlagSet, opts *install.Options) {
+	opts.Diagnostics = diagnose.DefaultOptions
+	flags.StringVar(&opts.Diagnostics.File, "diagnostics-file", "", "Also write the diagnosis collected when a wait fails to the given file")
+	flags.Int64Var(&opts.Diagnostics.LogLines, "diagnostics-log-lines", diagnose.DefaultOptions.LogLines, "Number of log lines collected from each failing container when a wait fails")
+}
+
+func initTimeoutFlags(flags *pflag.FlagSet, opts *install.Options) {
+	flags.DurationVar(&opts.Timeout, "timeout", 0, "Overall time budget of the waits, e.g. 45m. The waits are only bounded by their own timeouts when 0")
+	flags.StringToStringVar(&waitTimeouts, "wait-timeout", nil, "Timeouts replacing the timeouts of the wait stages of the profile, e.g. ds=20m,am=15m. The stages of the default profile are secrets, git-server, ds, am and amster")
+}
+
+// configureWaitTimeouts resolves the stage timeouts given with --wait-timeout
+func configureWaitTimeouts(opts *install.Options) error {
+	if len(waitTimeouts) == 0 {
+		return nil
+	}
+	timeouts, err := install.ParseWaitTimeouts(waitTimeouts, profile.Current())
+	if err != nil {
+		return err
+	}
+	opts.WaitTimeouts = timeouts
+	return nil
+}
+
+func initImageFlags(flags *pflag.FlagSet, opts *install.Options) {
+	flags.StringVar(&opts.ImageRegistry, "image-registry", "", "Pull the workload images from the given registry instead of their original registry, e.g. mirror.corp/forgerock")
+	flags.StringToStringVar(&opts.SetImages, "set-image", nil, "Image used by the containers with the given name, e.g. am=repo/am:tag. Can be repeated")
+}
+
 func init() {
 	// Install k8s flags
 	installFlags = initK8sFlags(installCmd.PersistentFlags())
+	initManifestSourceFlags(installCmd.PersistentFlags())
 
 	// Install command-specific flags
 	installCmd.PersistentFlags().StringVarP(&tag, "tag", "t", "latest", "Release tag  of the component to be deployed")
+	installCmd.PersistentFlags().StringVar(&dryRun, "dry-run", "", "(options: client|server) client prints the objects that would be applied. server submits them to the API server with dryRun=All. Nothing is persisted")
+	installCmd.PersistentFlags().Lookup("dry-run").NoOptDefVal = string(install.DryRunClient)
+	installCmd.PersistentFlags().BoolVar(&installOptions.Prune, "prune", false, "Delete the objects previously installed with a component that are no longer part of its manifest")
+	installCmd.PersistentFlags().BoolVar(&installOptions.ForceConflicts, "force-conflicts", false, "Take ownership of fields owned by other field managers, e.g. the secret-agent or ds-operator. By default conflicting fields are reported and not changed")
+	initImageFlags(installCmd.PersistentFlags(), &installOptions)
+	initDiagnosticsFlags(installCmd.PersistentFlags(), &installOptions)
+	initSizeFlags(installCmd.PersistentFlags())
+	initMetadataFlags(installCmd.PersistentFlags(), &installOptions)
+	installCmd.Flags().StringVar(&fqdn, "fqdn", "", "FQDN used in the deployment. (default \"[NAMESPACE].iam.example.com\")")
 	quickstart.PersistentFlags().StringVar(&fqdn, "fqdn", "", "FQDN used in the deployment. (default \"[NAMESPACE].iam.example.com\")")
+	quickstart.Flags().BoolVar(&installOptions.Resume, "resume", false, "Continue the quickstart from the first step that didn't complete. The steps completed are checkpointed in the namespace")
+	initTimeoutFlags(quickstart.Flags(), &installOptions)
+	custom.Flags().StringVarP(&customPath, "filename", "f", "", "File, directory or URL containing the manifests to install")
+	custom.Flags().StringP("kustomize", "k", "", "Kustomization directory to build and install")
+	custom.Flags().BoolVarP(&customPathOptions.Recursive, "recursive", "R", false, "Process the directory used in -f recursively")
+	custom.Flags().StringVar(&customComponent, "component", "", "Component the objects are labeled with. Required when the name of the path isn't a valid label value or is a component of the profile. (default the name of the path)")
 	installCmd.AddCommand(quickstart)
+	installCmd.AddCommand(custom)
 	installCmd.AddCommand(secretAgent)
 	installCmd.AddCommand(dsOperator)
 	generateFRComponentInstallCommands()
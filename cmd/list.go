@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// cmd globals config
+var (
+	listFlags         *genericclioptions.ConfigFlags
+	listAllNamespaces bool
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the components installed by the forgeops-cli",
+	Long: `
+    List the components installed by the forgeops-cli:
+    * Reads the inventory recorded in the namespace by "forgeops install"
+    * Shows the version, FQDN, checksum of the manifest and number of objects of each component
+    * Use --all-namespaces to list the components of every namespace`,
+	Example: `
+    # List the components installed in the current namespace.
+    forgeops list
+
+    # List the components installed in every namespace in json format.
+    forgeops list -A -o json`,
+	// Configure Client Mgr for all subcommands
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.Parent().PersistentPreRun(cmd.Parent(), args)
+		clientFactory = factory.NewFactory(listFlags)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		records, err := inventory.List(cmd.Context(), clientFactory, listAllNamespaces)
+		if err != nil {
+			return err
+		}
+		inventory.Print(records)
+		return nil
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+func init() {
+	// Install k8s flags
+	listFlags = initK8sFlags(listCmd.PersistentFlags())
+
+	listCmd.Flags().BoolVarP(&listAllNamespaces, "all-namespaces", "A", false, "List the components of every namespace")
+	rootCmd.AddCommand(listCmd)
+}
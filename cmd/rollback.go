@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/pkg/install"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// cmd globals config
+var (
+	rollbackFlags   *genericclioptions.ConfigFlags
+	rollbackTag     string
+	rollbackDryRun  string
+	rollbackOptions install.Options
+	// rollbackHealthTimeout time each tier is given to become healthy
+	rollbackHealthTimeout time.Duration
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [quickstart|COMPONENT]",
+	Short: "Roll back a component or the CDQ to a previously installed release",
+	Long: `
+    Roll back a component or the CDQ to a previously installed release:
+    * The release installed before the current one is read from the inventory recorded by "forgeops install"
+    * Use --tag to roll back to a different version
+    * Objects added by the newer release are deleted
+    * The CDQ is rolled back tier by tier like an upgrade. The amster job isn't run again
+    * The rollback stops at the first tier that doesn't become healthy within --health-timeout
+    * Use --timeout to bound the whole rollback`,
+	Example: `
+    # Roll back the CDQ in a given namespace to the previously installed release.
+    forgeops rollback quickstart --namespace mynamespace
+
+    # Roll back the ForgeRock "apps" to a given version.
+    forgeops rollback apps --tag 2020.10.28-AlSugoDiNoci
+
+    # List what installed components can be rolled back to.
+    forgeops list`,
+	Args: cobra.ExactArgs(1),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Root().PersistentPreRun(cmd.Root(), args)
+		clientFactory = factory.NewFactory(rollbackFlags)
+		strategy, err := install.ParseDryRunStrategy(rollbackDryRun)
+		if err != nil {
+			return err
+		}
+		rollbackOptions.DryRun = strategy
+		install.ConfigureDryRunOutput(strategy)
+		if err := install.ValidateMetadata(rollbackOptions.Labels, rollbackOptions.Annotations); err != nil {
+			return err
+		}
+		if err := configureSize(&rollbackOptions); err != nil {
+			return err
+		}
+		return configureManifestSource()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "quickstart", "qs":
+			return install.RollbackQuickstart(cmd.Context(), clientFactory, rollbackTag, rollbackHealthTimeout, rollbackOptions)
+		}
+		return install.Rollback(cmd.Context(), clientFactory, args[0], rollbackTag, rollbackHealthTimeout, rollbackOptions)
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+func init() {
+	// Install k8s flags
+	rollbackFlags = initK8sFlags(rollbackCmd.PersistentFlags())
+	initManifestSourceFlags(rollbackCmd.PersistentFlags())
+
+	// Rollback command-specific flags
+	rollbackCmd.PersistentFlags().StringVarP(&rollbackTag, "tag", "t", "", "Release tag to roll back to. (default the release installed before the current one)")
+	rollbackCmd.PersistentFlags().StringVar(&rollbackDryRun, "dry-run", "", "(options: client|server) client prints the objects that would be applied. server submits them to the API server with dryRun=All. Nothing is persisted")
+	rollbackCmd.PersistentFlags().Lookup("dry-run").NoOptDefVal = string(install.DryRunClient)
+	rollbackCmd.PersistentFlags().BoolVar(&rollbackOptions.ForceConflicts, "force-conflicts", false, "Take ownership of fields owned by other field managers, e.g. the secret-agent or ds-operator. By default conflicting fields are reported and not changed")
+	initImageFlags(rollbackCmd.PersistentFlags(), &rollbackOptions)
+	initDiagnosticsFlags(rollbackCmd.PersistentFlags(), &rollbackOptions)
+	initSizeFlags(rollbackCmd.PersistentFlags())
+	initMetadataFlags(rollbackCmd.PersistentFlags(), &rollbackOptions)
+	rollbackCmd.PersistentFlags().DurationVar(&rollbackHealthTimeout, "health-timeout", 10*time.Minute, "Time each tier is given to become healthy")
+	rollbackCmd.PersistentFlags().DurationVar(&rollbackOptions.Timeout, "timeout", 0, "Overall time budget of the rollback, e.g. 45m. The tiers are only bounded by --health-timeout when 0")
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -1,10 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/cobra/doc"
@@ -20,6 +31,14 @@ var skipUserConfirmation bool
 var output string
 var logLevel string
 var outType printer.OutType
+var manifestDir string
+var bundleFile string
+var profileFile string
+var insecureSkipVerify bool
+var publicKeyFile string
+var httpOptions = utils.DefaultHTTPOptions
+var tokenFile string
+var gitHubAPIURL string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -54,6 +73,10 @@ var rootCmd = &cobra.Command{
 			printer.Errorln("Couldn't determine loglevel")
 			os.Exit(1)
 		}
+		if err := profile.Load(profileFile); err != nil {
+			printer.Errorln(err.Error())
+			os.Exit(1)
+		}
 	},
 }
 
@@ -62,17 +85,73 @@ func Doc() {
 	doc.GenMarkdownTree(rootCmd, "./docs")
 }
 
+// exitError fails a command with the given exit code. The error is printed unless it's nil,
+// e.g. diff exits with 1 when objects would change
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e exitError) Unwrap() error {
+	return e.err
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Commands are cancelled on SIGINT or SIGTERM
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signalContext()
+	defer stop()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		code := 1
+		var exitErr exitError
+		if errors.As(err, &exitErr) {
+			code, err = exitErr.code, exitErr.err
+		}
+		if err == nil {
+			os.Exit(code)
+		}
 		printer.Errorln(err.Error())
-		os.Exit(1)
+		if errors.Is(err, context.Canceled) {
+			os.Exit(130)
+		}
+		os.Exit(code)
+	}
+}
+
+// signalContext returns a context cancelled on the first SIGINT or SIGTERM so the running command stops cleanly.
+// A second signal exits right away
+func signalContext() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			printer.Warnln("Interrupted. Stopping, press Ctrl-C again to exit right away")
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+		<-signals
+		os.Exit(130)
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "none", "(options: none|debug|info|warn|error) log statement level. When output=text and level is not 'none' the level is debug")
+	rootCmd.PersistentFlags().StringVar(&profileFile, "profile", "", "Path to a deployment profile declaring the placeholders, components, tiers and secrets of the deployment. The built-in CDQ profile is used by default")
 	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "(options: text|json) command output type. Type json is intended for use in scripting, text is for interactive usage. Not all commands provide both types of output")
 }
 
@@ -87,3 +166,66 @@ func initK8sFlags(flags *pflag.FlagSet) *genericclioptions.ConfigFlags {
 	flags.MarkHidden("cache-dir")
 	return kubeConfigFlags
 }
+
+func initManifestSourceFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&manifestDir, "manifest-dir", "", "Read the release manifests from a local directory instead of GitHub. Manifests are looked up as <owner>/<repo>/<tag>/<file>, \"latest\" being the newest tag. <file> at the root of the directory is used for \"latest\" or when the directory has no tags")
+	flags.StringVar(&bundleFile, "bundle", "", "Read the release manifests from a .tar.gz bundle created with \"forgeops bundle pull\" instead of GitHub")
+	flags.StringVar(&publicKeyFile, "public-key", "", "PEM encoded public key the signature of the release checksums (SHA256SUMS.sig) is verified with")
+	flags.BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Don't verify the release manifests against the checksums published with the release. Not recommended")
+	initDownloadFlags(flags)
+}
+
+func initDownloadFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&httpOptions.Timeout, "download-timeout", utils.DefaultHTTPOptions.Timeout, "Timeout of each attempt to download a release manifest")
+	flags.DurationVar(&httpOptions.ConnectTimeout, "connect-timeout", utils.DefaultHTTPOptions.ConnectTimeout, "Timeout to connect to the server hosting the release manifests")
+	flags.IntVar(&httpOptions.Retries, "download-retries", utils.DefaultHTTPOptions.Retries, "Retries of downloads failing with connection errors, 5xx or 429 responses. Retries back off exponentially")
+	flags.StringVar(&httpOptions.CAFile, "ca-file", "", "PEM encoded CA certificates trusted to download the release manifests, e.g. the CA of a corporate proxy")
+	flags.StringVar(&tokenFile, "token-file", "", "File containing a bearer token used to download the release assets of private repos through the GitHub API. Defaults to the GITHUB_TOKEN environment variable")
+	flags.StringVar(&gitHubAPIURL, "github-api-url", release.DefaultGitHubAPIURL, "GitHub API the releases are downloaded from, e.g. https://github.example.com/api/v3 for GitHub Enterprise")
+}
+
+// configureDownloads configures the client used to download the release manifests.
+// Proxies are read from the HTTPS_PROXY and NO_PROXY environment variables
+func configureDownloads() error {
+	opts := httpOptions
+	opts.Token = os.Getenv("GITHUB_TOKEN")
+	if len(tokenFile) > 0 {
+		token, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return err
+		}
+		opts.Token = strings.TrimSpace(string(token))
+	}
+	if err := utils.ConfigureHTTP(opts); err != nil {
+		return err
+	}
+	// The download URLs of the releases only serve the assets of public repos on github.com
+	return release.ConfigureGitHub(gitHubAPIURL, len(opts.Token) > 0 || gitHubAPIURL != release.DefaultGitHubAPIURL)
+}
+
+// configureManifestSource selects where the release manifests are obtained from
+func configureManifestSource() error {
+	if err := configureDownloads(); err != nil {
+		return err
+	}
+	source, err := release.NewSource(manifestDir, bundleFile)
+	if err != nil {
+		return err
+	}
+	if insecureSkipVerify {
+		if len(publicKeyFile) > 0 {
+			return errors.New("--public-key and --insecure-skip-verify are mutually exclusive")
+		}
+		printer.Warnf("The release manifests won't be verified")
+		release.SetSource(source)
+		return nil
+	}
+	var publicKey crypto.PublicKey
+	if len(publicKeyFile) > 0 {
+		if publicKey, err = release.LoadPublicKey(publicKeyFile); err != nil {
+			return err
+		}
+	}
+	release.SetSource(release.NewVerifyingSource(source, publicKey))
+	return nil
+}
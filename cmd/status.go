@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"context"
-
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
 	"github.com/ForgeRock/forgeops-cli/pkg/doctor"
@@ -57,8 +55,8 @@ var (
 				return err
 			}
 
-			confAllHealthy, confErr := health.Run(clientFactory, configHealth, true)
-			platAllHealthy, platErr := health.Run(clientFactory, platformHlth, false)
+			confAllHealthy, confErr := health.Run(cmd.Context(), clientFactory, configHealth, true)
+			platAllHealthy, platErr := health.Run(cmd.Context(), clientFactory, platformHlth, false)
 			if confErr != nil && platErr != nil {
 				return errors.Wrap(confErr, platErr.Error())
 			} else if !confAllHealthy || !platAllHealthy {
@@ -94,7 +92,7 @@ var (
 			if err != nil {
 				return err
 			}
-			operAllHealthy, err := health.Run(clientFactory, hlth, allNamespaces)
+			operAllHealthy, err := health.Run(cmd.Context(), clientFactory, hlth, allNamespaces)
 			if !operAllHealthy {
 				return health.ErrNotAllHealthy
 			}
@@ -131,8 +129,8 @@ var (
 				return err
 			}
 
-			operAllHealthy, operErr := health.Run(clientFactory, operatorHlth, true)
-			platAllHealthy, platErr := health.Run(clientFactory, platformHlth, false)
+			operAllHealthy, operErr := health.Run(cmd.Context(), clientFactory, operatorHlth, true)
+			platAllHealthy, platErr := health.Run(cmd.Context(), clientFactory, platformHlth, false)
 			if operErr != nil && platErr != nil {
 				return errors.Wrap(operErr, platErr.Error())
 			} else if !operAllHealthy || !platAllHealthy {
@@ -148,8 +146,6 @@ var (
 )
 
 func init() {
-	ctx = context.Background()
-
 	// Install k8s flags
 	statusFlags = initK8sFlags(statusCmd.PersistentFlags())
 
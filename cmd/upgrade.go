@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/install"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// cmd globals config
+var (
+	upgradeFlags         *genericclioptions.ConfigFlags
+	upgradeDryRun        string
+	upgradeHealthTimeout time.Duration
+	upgradeOptions       install.Options
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Args:  noArgs,
+	Short: "Upgrade the ForgeRock Cloud Deployment Quickstart (CDQ) to a different version",
+	Long: `
+    Upgrade the ForgeRock Cloud Deployment Quickstart (CDQ) installed in the namespace to a different version:
+    * The tiers are upgraded in order: base, directory, apps and ui
+    * Each tier must become healthy before the next tier is upgraded
+    * The upgrade stops at the first tier that doesn't become healthy within --health-timeout
+    * Use --timeout to bound the whole upgrade. --health-timeout, not --wait-timeout, bounds each tier
+    * The resources of a tier that doesn't become healthy are diagnosed
+    * The amster job isn't run again. The configuration it imported is kept`,
+	Example: `
+    # Upgrade the CDQ in a given namespace to a given version.
+    forgeops upgrade --tag 2020.10.28-AlSugoDiNoci --namespace mynamespace
+
+    # Show the objects that would be applied by the upgrade.
+    forgeops upgrade --tag 2020.10.28-AlSugoDiNoci --dry-run=server`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Root().PersistentPreRun(cmd.Root(), args)
+		clientFactory = factory.NewFactory(upgradeFlags)
+		strategy, err := install.ParseDryRunStrategy(upgradeDryRun)
+		if err != nil {
+			return err
+		}
+		upgradeOptions.DryRun = strategy
+		install.ConfigureDryRunOutput(strategy)
+		if err := install.ValidateMetadata(upgradeOptions.Labels, upgradeOptions.Annotations); err != nil {
+			return err
+		}
+		if err := configureSize(&upgradeOptions); err != nil {
+			return err
+		}
+		return configureManifestSource()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return install.Upgrade(cmd.Context(), clientFactory, release.ForgeOpsRepo, tag, fqdn, upgradeHealthTimeout, upgradeOptions)
+	},
+	SilenceUsage:      true,
+	DisableAutoGenTag: true,
+}
+
+func init() {
+	// Install k8s flags
+	upgradeFlags = initK8sFlags(upgradeCmd.PersistentFlags())
+	initManifestSourceFlags(upgradeCmd.PersistentFlags())
+
+	// Upgrade command-specific flags
+	upgradeCmd.Flags().StringVarP(&tag, "tag", "t", "latest", "Release tag of the CDQ to upgrade to")
+	upgradeCmd.Flags().StringVar(&fqdn, "fqdn", "", "FQDN used in the deployment. (default the FQDN the CDQ was installed with)")
+	upgradeCmd.Flags().DurationVar(&upgradeHealthTimeout, "health-timeout", 10*time.Minute, "Time each tier is given to become healthy")
+	upgradeCmd.Flags().StringVar(&upgradeDryRun, "dry-run", "", "(options: client|server) client prints the objects that would be applied. server submits them to the API server with dryRun=All. Nothing is persisted")
+	upgradeCmd.Flags().Lookup("dry-run").NoOptDefVal = string(install.DryRunClient)
+	upgradeCmd.Flags().BoolVar(&upgradeOptions.Prune, "prune", false, "Delete the objects previously installed with a component that are no longer part of its manifest")
+	upgradeCmd.Flags().BoolVar(&upgradeOptions.ForceConflicts, "force-conflicts", false, "Take ownership of fields owned by other field managers, e.g. the secret-agent or ds-operator. By default conflicting fields are reported and not changed")
+	initImageFlags(upgradeCmd.Flags(), &upgradeOptions)
+	initDiagnosticsFlags(upgradeCmd.Flags(), &upgradeOptions)
+	upgradeCmd.Flags().DurationVar(&upgradeOptions.Timeout, "timeout", 0, "Overall time budget of the upgrade, e.g. 45m. The tiers are only bounded by --health-timeout when 0")
+	initSizeFlags(upgradeCmd.Flags())
+	initMetadataFlags(upgradeCmd.Flags(), &upgradeOptions)
+	rootCmd.AddCommand(upgradeCmd)
+}
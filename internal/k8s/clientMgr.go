@@ -1,20 +1,25 @@
 package k8s
 
 import (
+	"context"
 	"fmt"
 	"io"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
 )
 
 // NewK8sClientMgr create a new instance of NewK8sClientMgr
@@ -28,21 +33,40 @@ func NewK8sClientMgr(f factory.Factory) ClientMgr {
 type ClientMgr interface {
 	Factory() factory.Factory
 	Namespace() (string, error)
-	GetObjectsFromPath(path string) ([]*resource.Info, error)
+	GetObjectsFromPath(path string, opts PathOptions) ([]*resource.Info, error)
 	GetObjectsFromStream(reader io.Reader) ([]*resource.Info, error)
-	GetObjectsFromServer(resourceType, name string) ([]*resource.Info, error)
-	ApplyObject(info *resource.Info) error
-	DeleteObject(info *resource.Info) error
-	WatchEventsForCondition(timeoutSecs int, ns, name string, gvr schema.GroupVersionResource, condition ConditionFunction) (bool, error)
-	WaitForResource(timeoutSecs int, ns, name string, gvr schema.GroupVersionResource) (bool, error)
-	WaitForResourceStatusCondition(timeoutSecs int, ns, name, conditionStr string, gvr schema.GroupVersionResource) (bool, error)
-	WaitForResourceReplicas(timeoutSecs int, ns, name, replicas string, gvr schema.GroupVersionResource) (bool, error)
+	GetObjectsFromServer(ctx context.Context, resourceType, name string) ([]*resource.Info, error)
+	MapObjects(infos []*resource.Info) error
+	ResetRESTMapper() error
+	ListObjects(ctx context.Context, ns, resourceType, labelSelector string) ([]*resource.Info, error)
+	ApplyObject(ctx context.Context, info *resource.Info, opts ApplyOptions) error
+	DeleteObject(ctx context.Context, info *resource.Info) error
+	WatchEventsForCondition(ctx context.Context, timeoutSecs int, ns, name string, gvr schema.GroupVersionResource, condition ConditionFunction) (bool, error)
+	WaitForResource(ctx context.Context, timeoutSecs int, ns, name string, gvr schema.GroupVersionResource) (bool, error)
+	WaitForResourceStatusCondition(ctx context.Context, timeoutSecs int, ns, name, conditionStr string, gvr schema.GroupVersionResource) (bool, error)
+	WaitForResourceReplicas(ctx context.Context, timeoutSecs int, ns, name, replicas string, gvr schema.GroupVersionResource) (bool, error)
 }
 
 type clientMgr struct {
 	factory factory.Factory
 }
 
+// ApplyOptions controls how objects are applied
+type ApplyOptions struct {
+	// DryRun sends the request with dryRun=All. Nothing is persisted
+	DryRun bool
+	// ForceConflicts takes ownership of fields owned by other field managers
+	ForceConflicts bool
+}
+
+// PathOptions controls how manifests are read from a path
+type PathOptions struct {
+	// Kustomize builds the kustomization in the path instead of reading the manifests as they are
+	Kustomize bool
+	// Recursive processes the directories in the path recursively
+	Recursive bool
+}
+
 // NullSchema always validates bytes.
 type NullSchema struct{}
 
@@ -63,16 +87,16 @@ func (cmgr clientMgr) Namespace() (string, error) {
 	return ns, nil
 }
 
-// GetObjectsFromPath Obtains objects from filepath or url
-func (cmgr clientMgr) GetObjectsFromPath(path string) ([]*resource.Info, error) {
+// GetObjectsFromPath Obtains objects from filepath, url or kustomization directory
+func (cmgr clientMgr) GetObjectsFromPath(path string, opts PathOptions) ([]*resource.Info, error) {
 	usage := "contains the manifest to process"
-	cfg, err := cmgr.factory.GetOverrideFlags()
-	if err != nil {
-		return nil, err
-	}
 	filenames := []string{path}
 	kustomize := ""
-	recursive := false
+	if opts.Kustomize {
+		filenames = []string{}
+		kustomize = path
+	}
+	recursive := opts.Recursive
 	fileNameFlags := &genericclioptions.FileNameFlags{
 		Usage:     usage,
 		Filenames: &filenames,
@@ -81,42 +105,147 @@ func (cmgr clientMgr) GetObjectsFromPath(path string) ([]*resource.Info, error)
 	}
 	fileNameOpts := fileNameFlags.ToOptions()
 	builder := cmgr.factory.Builder()
+	// Objects are read locally and mapped afterwards. Custom resources can be read before their CRD is installed
 	r := builder.
+		Local().
 		Unstructured().
 		Schema(NullSchema{}).
 		ContinueOnError().
-		// Use cfg.Namespace in case there's an override. Otherwise default to the ns in the manifest
-		NamespaceParam(*cfg.Namespace).DefaultNamespace().
 		FilenameParam(false, &fileNameOpts).
 		Flatten().
 		Do()
 	objects, err := r.Infos()
-	return objects, err
-}
-
-// GetObjectsFromPath Obtains objects from a io.Reader stream
-func (cmgr clientMgr) GetObjectsFromStream(reader io.Reader) ([]*resource.Info, error) {
-	cfg, err := cmgr.factory.GetOverrideFlags()
 	if err != nil {
 		return nil, err
 	}
+	return objects, cmgr.MapObjects(objects)
+}
 
+// GetObjectsFromPath Obtains objects from a io.Reader stream
+func (cmgr clientMgr) GetObjectsFromStream(reader io.Reader) ([]*resource.Info, error) {
 	builder := cmgr.factory.Builder()
+	// Objects are read locally and mapped afterwards. Custom resources can be read before their CRD is installed
 	r := builder.
+		Local().
 		Unstructured().
 		Schema(NullSchema{}).
 		ContinueOnError().
-		// Use cfg.Namespace in case there's an override. Otherwise default to the ns in the manifest
-		NamespaceParam(*cfg.Namespace).DefaultNamespace().
 		Stream(reader, "stream").
 		Flatten().
 		Do()
 	objects, err := r.Infos()
-	return objects, err
+	if err != nil {
+		return nil, err
+	}
+	return objects, cmgr.MapObjects(objects)
+}
+
+// MapObjects sets the REST mapping and client of the objects without them.
+// Namespaced objects without a namespace are set in the --namespace override, if any.
+// Custom resources of the CRDs in infos are left unmapped until the CRD is established. Call MapObjects
+// again once the CRD is established and the REST mapper is reset
+func (cmgr clientMgr) MapObjects(infos []*resource.Info) error {
+	cfg, err := cmgr.factory.GetOverrideFlags()
+	if err != nil {
+		return err
+	}
+	mapper, err := cfg.ToRESTMapper()
+	if err != nil {
+		return err
+	}
+	restConfig, err := cmgr.factory.RestConfig()
+	if err != nil {
+		return err
+	}
+	crdKinds := CRDKinds(infos)
+	clients := map[schema.GroupVersion]resource.RESTClient{}
+	errs := []error{}
+	for _, info := range infos {
+		if info.Mapping != nil {
+			continue
+		}
+		gvk := info.Object.GetObjectKind().GroupVersionKind()
+		mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) && crdKinds[gvk.GroupKind()] {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to recognize %q: %v", info.Source, err))
+			continue
+		}
+		client, ok := clients[gvk.GroupVersion()]
+		if !ok {
+			if client, err = unstructuredClient(restConfig, gvk.GroupVersion()); err != nil {
+				return err
+			}
+			clients[gvk.GroupVersion()] = client
+		}
+		info.Mapping = mapping
+		info.Client = client
+		// Use cfg.Namespace in case there's an override. Otherwise default to the ns in the manifest
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && len(info.Namespace) == 0 && len(*cfg.Namespace) > 0 {
+			info.Namespace = *cfg.Namespace
+			if err := meta.NewAccessor().SetNamespace(info.Object, info.Namespace); err != nil {
+				return err
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// ResetRESTMapper refreshes the cached API discovery so the kinds of CRDs established since are mapped
+func (cmgr clientMgr) ResetRESTMapper() error {
+	cfg, err := cmgr.factory.GetOverrideFlags()
+	if err != nil {
+		return err
+	}
+	discoveryClient, err := cfg.ToDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	discoveryClient.Invalidate()
+	// Rewrite the cache read by the REST mappers created afterwards
+	if _, _, err := discoveryClient.ServerGroupsAndResources(); err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return err
+	}
+	return nil
+}
+
+// CRDKinds returns the kinds defined by the CustomResourceDefinitions in infos
+func CRDKinds(infos []*resource.Info) map[schema.GroupKind]bool {
+	kinds := map[schema.GroupKind]bool{}
+	for gk := range CRDScopes(infos) {
+		kinds[gk] = true
+	}
+	return kinds
 }
 
-// if no name is provided, this function will return all objects of the given type
-func (cmgr clientMgr) GetObjectsFromServer(resourceType, name string) ([]*resource.Info, error) {
+// crdGroupKind kind of the CustomResourceDefinitions
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// unstructuredClient creates a REST client for the group version as the resource.Builder does
+func unstructuredClient(config *rest.Config, gv schema.GroupVersion) (resource.RESTClient, error) {
+	cfg := rest.CopyConfig(config)
+	cfg.ContentConfig = resource.UnstructuredPlusDefaultContentConfig()
+	cfg.GroupVersion = &gv
+	if len(gv.Group) == 0 {
+		cfg.APIPath = "/api"
+	} else {
+		cfg.APIPath = "/apis"
+	}
+	client, err := rest.RESTClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return resource.NewClientWithOptions(client), nil
+}
+
+// if no name is provided, this function will return all objects of the given type.
+// The builder doesn't take a context, a cancelled context is only checked before listing
+func (cmgr clientMgr) GetObjectsFromServer(ctx context.Context, resourceType, name string) ([]*resource.Info, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	ns, err := cmgr.Namespace()
 	if err != nil {
 		return nil, err
@@ -142,10 +271,37 @@ func (cmgr clientMgr) GetObjectsFromServer(resourceType, name string) ([]*resour
 	return objects, err
 }
 
-// ApplyObject Applies changes to the object
-func (cmgr clientMgr) ApplyObject(info *resource.Info) error {
-	helper := resource.NewHelper(info.Client, info.Mapping).
-		WithFieldManager("forgeops-cli")
+// ListObjects returns the objects of the given type matching the label selector in the namespace provided.
+// The builder doesn't take a context, a cancelled context is only checked before listing
+func (cmgr clientMgr) ListObjects(ctx context.Context, ns, resourceType, labelSelector string) ([]*resource.Info, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	builder := cmgr.factory.Builder()
+	r := builder.
+		Unstructured().
+		ContinueOnError().
+		NamespaceParam(ns).DefaultNamespace().
+		LabelSelectorParam(labelSelector).
+		SingleResourceType().
+		ResourceTypes(resourceType).
+		Flatten().
+		Do()
+	objects, err := r.Infos()
+	return objects, err
+}
+
+// ApplyObject Applies the object using server-side apply.
+// Fields owned by other field managers are not changed unless opts.ForceConflicts is set.
+// Fields owned by the updates of previous versions of the forgeops-cli are taken over by the apply
+func (cmgr clientMgr) ApplyObject(ctx context.Context, info *resource.Info, opts ApplyOptions) error {
+	dryRunSuffix := ""
+	patchOpts := &metav1.PatchOptions{Force: &opts.ForceConflicts, FieldManager: FieldManager}
+	if opts.DryRun {
+		dryRunSuffix = " (server dry run)"
+		patchOpts.DryRun = []string{metav1.DryRunAll}
+	}
+	kind := info.ResourceMapping().GroupVersionKind.Kind
 
 	// Clear "managedFields" before patching
 	unstructured.RemoveNestedField(info.Object.(*unstructured.Unstructured).Object, "metadata", "managedFields")
@@ -159,47 +315,42 @@ func (cmgr clientMgr) ApplyObject(info *resource.Info) error {
 		return fmt.Errorf("error when %s %q: %v", "serverside-apply", info.Source, err)
 	}
 
-	// if the object doesn't exist. Create it. Otherwise, patch it.
-	if err := info.Get(); err != nil {
-		if apierrors.IsNotFound(err) {
-			obj, err := helper.Create(info.Namespace, true, info.Object)
-			if err != nil {
-				return err
-			}
-			info.Refresh(obj, true)
-			printer.Noticef(fmt.Sprintf("%s %q created", info.ResourceMapping().GroupVersionKind.Kind, info.Name))
-			return nil
-		}
-		return err
+	// Send the full object to be applied on the server side. Apply creates the object if it doesn't exist
+	apply := func() (runtime.Object, error) {
+		return request(info, info.Client.Patch(types.ApplyPatchType)).
+			VersionedParams(patchOpts, metav1.ParameterCodec).
+			Body(data).
+			Do(ctx).
+			Get()
 	}
-	// The object exist. Patch/Apply instead
-	// Send the full object to be applied on the server side.
-	obj, err := helper.Patch(
-		info.Namespace,
-		info.Name,
-		types.MergePatchType,
-		data,
-		nil,
-	)
+	obj, err := apply()
 	if err != nil {
-		return err
+		err = newApplyConflictError(kind, info.Name, err)
+		// Previous versions of the forgeops-cli updated the objects with the same field manager. The server tracks
+		// these updates apart from the apply, only the fields they own conflict. The apply takes them over
+		conflictErr, ok := err.(*ApplyConflictError)
+		if !ok || !conflictErr.selfOwned() {
+			return err
+		}
+		force := true
+		patchOpts.Force = &force
+		if obj, err = apply(); err != nil {
+			return newApplyConflictError(kind, info.Name, err)
+		}
 	}
 	info.Refresh(obj, true)
-	printer.Noticef(fmt.Sprintf("%s %q applied", info.ResourceMapping().GroupVersionKind.Kind, info.Name))
+	printer.Noticef(fmt.Sprintf("%s %q applied%s", kind, info.Name, dryRunSuffix))
 	return nil
 }
 
-func (cmgr clientMgr) DeleteObject(info *resource.Info) error {
-	helper := resource.NewHelper(info.Client, info.Mapping).
-		WithFieldManager("forgeops-cli")
-
+func (cmgr clientMgr) DeleteObject(ctx context.Context, info *resource.Info) error {
 	var gracePeriodSeconds int64 = 30
 	propagationPolicy := metav1.DeletePropagationBackground
 	options := metav1.DeleteOptions{
 		GracePeriodSeconds: &gracePeriodSeconds,
 		PropagationPolicy:  &propagationPolicy,
 	}
-	obj, err := helper.DeleteWithOptions(info.Namespace, info.Name, &options)
+	obj, err := request(info, info.Client.Delete()).Body(&options).Do(ctx).Get()
 	if err != nil {
 		// Ignore notFound errors when deleting objects
 		if apierrors.IsNotFound(err) {
@@ -212,3 +363,10 @@ func (cmgr clientMgr) DeleteObject(info *resource.Info) error {
 	printer.Noticef(fmt.Sprintf("%s %q deleted", info.ResourceMapping().GroupVersionKind.Kind, info.Name))
 	return nil
 }
+
+// request targets the object of info with the request provided, as the resource.Helper does
+func request(info *resource.Info, r *rest.Request) *rest.Request {
+	return r.NamespaceIfScoped(info.Namespace, info.Mapping.Scope.Name() == meta.RESTScopeNameNamespace).
+		Resource(info.Mapping.Resource.Resource).
+		Name(info.Name)
+}
@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// TestCRDKinds tests the kinds defined by CRDs are found
+func TestCRDKinds(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "directoryservices.directory.forgerock.io"},
+		"spec": map[string]interface{}{
+			"group": "directory.forgerock.io",
+			"names": map[string]interface{}{"kind": "DirectoryService", "plural": "directoryservices"},
+		},
+	}}
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "platform-config"},
+	}}
+	kinds := CRDKinds([]*resource.Info{{Object: crd}, {Object: cm}})
+	if len(kinds) != 1 || !kinds[schema.GroupKind{Group: "directory.forgerock.io", Kind: "DirectoryService"}] {
+		t.Errorf("unexpected kinds: %v", kinds)
+	}
+}
@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FieldManager field manager the objects are applied with
+const FieldManager = "forgeops-cli"
+
+// managerRegexp extracts the field manager from a conflict cause, e.g. conflict with "secret-agent" using v1
+var managerRegexp = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// ApplyConflictError is returned when a server-side apply tries to change fields owned by other field managers
+type ApplyConflictError struct {
+	Kind string
+	Name string
+	// Fields owned by each of the conflicting managers
+	Fields map[string][]string
+}
+
+func (e *ApplyConflictError) Error() string {
+	managers := make([]string, 0, len(e.Fields))
+	for manager := range e.Fields {
+		managers = append(managers, manager)
+	}
+	sort.Strings(managers)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %q has fields owned by other managers. Use --force-conflicts to take ownership:", e.Kind, e.Name)
+	for _, manager := range managers {
+		fmt.Fprintf(&sb, "\n  %q owns %s", manager, strings.Join(e.Fields[manager], ", "))
+	}
+	return sb.String()
+}
+
+// selfOwned reports whether all the conflicting fields are owned by the forgeops-cli itself,
+// i.e. by the updates of its previous versions
+func (e *ApplyConflictError) selfOwned() bool {
+	_, ok := e.Fields[FieldManager]
+	return ok && len(e.Fields) == 1
+}
+
+// newApplyConflictError converts a server-side apply conflict into an ApplyConflictError.
+// The original error is returned if it's not a conflict
+func newApplyConflictError(kind, name string, err error) error {
+	if !apierrors.IsConflict(err) {
+		return err
+	}
+	statusErr, ok := err.(apierrors.APIStatus)
+	if !ok || statusErr.Status().Details == nil {
+		return err
+	}
+	conflictErr := &ApplyConflictError{
+		Kind:   kind,
+		Name:   name,
+		Fields: map[string][]string{},
+	}
+	for _, cause := range statusErr.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		manager := "unknown"
+		if match := managerRegexp.FindStringSubmatch(cause.Message); match != nil {
+			manager = match[1]
+		}
+		conflictErr.Fields[manager] = append(conflictErr.Fields[manager], cause.Field)
+	}
+	if len(conflictErr.Fields) == 0 {
+		return err
+	}
+	return conflictErr
+}
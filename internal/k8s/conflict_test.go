@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/rest/fake"
+)
+
+// TestNewApplyConflictError tests conflicts are reported per field and manager
+func TestNewApplyConflictError(t *testing.T) {
+	conflict := apierrors.NewApplyConflict([]metav1.StatusCause{
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "secret-agent" using v1`,
+			Field:   ".data.password",
+		},
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "ds-operator" using directory.forgerock.io/v1alpha1`,
+			Field:   ".spec.replicas",
+		},
+		{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "secret-agent" using v1`,
+			Field:   ".data.user",
+		},
+	}, "Apply failed with 3 conflicts")
+
+	err := newApplyConflictError("Secret", "ds-passwords", conflict)
+	conflictErr, ok := err.(*ApplyConflictError)
+	if !ok {
+		t.Fatalf("expected an ApplyConflictError, found: %+v", err)
+	}
+	expected := map[string][]string{
+		"secret-agent": {".data.password", ".data.user"},
+		"ds-operator":  {".spec.replicas"},
+	}
+	if !reflect.DeepEqual(conflictErr.Fields, expected) {
+		t.Errorf("expected: %+v, found: %+v", expected, conflictErr.Fields)
+	}
+
+	// errors other than conflicts are returned as is
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "ds-passwords")
+	if err := newApplyConflictError("Secret", "ds-passwords", notFound); !errors.Is(err, notFound) {
+		t.Errorf("expected: %+v, found: %+v", notFound, err)
+	}
+}
+
+// TestApplyObjectConflicts tests the fields owned by the updates of previous versions of the forgeops-cli are taken over
+// while the conflicts with other managers are reported
+func TestApplyObjectConflicts(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		// manager owning the conflicting field
+		manager string
+		// expected requests, e.g. PATCH force=false
+		expected  []string
+		expectErr bool
+	}{
+		{
+			testComment: "fields updated by previous versions of the forgeops-cli are forced",
+			manager:     FieldManager,
+			expected:    []string{"PATCH force=false", "PATCH force=true"},
+			expectErr:   false,
+		},
+		{
+			testComment: "fields owned by other managers are reported",
+			manager:     "secret-agent",
+			expected:    []string{"PATCH force=false"},
+			expectErr:   true,
+		},
+	}
+	for _, tc := range td {
+		requests := []string{}
+		client := &fake.RESTClient{
+			NegotiatedSerializer: resource.UnstructuredPlusDefaultContentConfig().NegotiatedSerializer,
+			Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				force := req.URL.Query().Get("force")
+				requests = append(requests, req.Method+" force="+force)
+				status := http.StatusOK
+				var body interface{} = map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata":   map[string]interface{}{"name": "platform-config", "namespace": "prod"},
+				}
+				if force != "true" {
+					statusErr := apierrors.NewApplyConflict([]metav1.StatusCause{{
+						Type:    metav1.CauseTypeFieldManagerConflict,
+						Message: `conflict with "` + tc.manager + `" using v1 at 2020-10-28T00:00:00Z`,
+						Field:   ".data.FQDN",
+					}}, "Apply failed with 1 conflict")
+					statusErr.ErrStatus.APIVersion, statusErr.ErrStatus.Kind = "v1", "Status"
+					status, body = http.StatusConflict, statusErr.ErrStatus
+				}
+				data, err := json.Marshal(body)
+				if err != nil {
+					return nil, err
+				}
+				header := http.Header{"Content-Type": []string{"application/json"}}
+				return &http.Response{StatusCode: status, Header: header, Body: ioutil.NopCloser(bytes.NewReader(data))}, nil
+			}),
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("platform-config")
+		obj.SetNamespace("prod")
+		info := &resource.Info{
+			Client:    client,
+			Name:      "platform-config",
+			Namespace: "prod",
+			Object:    obj,
+			Mapping: &meta.RESTMapping{
+				Resource:         schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+				GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+				Scope:            meta.RESTScopeNamespace,
+			},
+		}
+		err := clientMgr{}.ApplyObject(context.Background(), info, ApplyOptions{})
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+		}
+		if !reflect.DeepEqual(requests, tc.expected) {
+			t.Errorf("%s expected requests: %v, found: %v", tc.testComment, tc.expected, requests)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// SetNamespace sets the namespace of a namespaced object whose namespace is the placeholder or empty.
+// Custom resources are left unmapped until their CRD is established, they take the scope of their CRD in crdScopes.
+// Objects of unknown scope are left as they are.
+// The namespace of RoleBinding and ClusterRoleBinding subjects is replaced as well. The fields changed are returned
+func SetNamespace(info *resource.Info, placeholder, ns string, crdScopes map[schema.GroupKind]meta.RESTScopeName) ([]string, error) {
+	obj, ok := info.Object.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+	changed := []string{}
+	scope := crdScopes[obj.GroupVersionKind().GroupKind()]
+	if info.Mapping != nil {
+		scope = info.Mapping.Scope.Name()
+	}
+	namespaced := scope == meta.RESTScopeNameNamespace
+	if namespaced && obj.GetNamespace() != ns && (obj.GetNamespace() == placeholder || obj.GetNamespace() == "") {
+		obj.SetNamespace(ns)
+		info.Namespace = ns
+		changed = append(changed, "metadata.namespace")
+	}
+	switch obj.GetKind() {
+	case "RoleBinding", "ClusterRoleBinding":
+		subjects, _, err := unstructured.NestedSlice(obj.Object, "subjects")
+		if err != nil {
+			return nil, err
+		}
+		subjectsChanged := false
+		for i, s := range subjects {
+			subject, ok := s.(map[string]interface{})
+			if ok && subject["namespace"] == placeholder && placeholder != ns {
+				subject["namespace"] = ns
+				subjectsChanged = true
+				changed = append(changed, fmt.Sprintf("subjects[%d].namespace", i))
+			}
+		}
+		if subjectsChanged {
+			if err := unstructured.SetNestedSlice(obj.Object, subjects, "subjects"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return changed, nil
+}
+
+// CRDScopes returns the scope of the kinds defined by the CustomResourceDefinitions in infos
+func CRDScopes(infos []*resource.Info) map[schema.GroupKind]meta.RESTScopeName {
+	scopes := map[schema.GroupKind]meta.RESTScopeName{}
+	for _, info := range infos {
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok || obj.GroupVersionKind().GroupKind() != crdGroupKind {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		scope, _, _ := unstructured.NestedString(obj.Object, "spec", "scope")
+		var name meta.RESTScopeName
+		switch scope {
+		case "Namespaced":
+			name = meta.RESTScopeNameNamespace
+		case "Cluster":
+			name = meta.RESTScopeNameRoot
+		}
+		scopes[schema.GroupKind{Group: group, Kind: kind}] = name
+	}
+	return scopes
+}
@@ -42,16 +42,17 @@ func ConditionExpression(expression string) ConditionFunction {
 	}
 }
 
-// WatchEventsForCondition sets a watch for events and evaluatest the provided ConditionFunction
-func (cmgr clientMgr) WatchEventsForCondition(timeoutSecs int, ns, name string, gvr schema.GroupVersionResource, condition ConditionFunction) (bool, error) {
+// WatchEventsForCondition sets a watch for events and evaluatest the provided ConditionFunction.
+// The timeout bounds the whole wait, including the watches restarted after they're closed
+func (cmgr clientMgr) WatchEventsForCondition(ctx context.Context, timeoutSecs int, ns, name string, gvr schema.GroupVersionResource, condition ConditionFunction) (bool, error) {
+	endTime := time.Now().Add(time.Duration(timeoutSecs) * time.Second)
 	for {
-		endTime := time.Now().Add(time.Duration(timeoutSecs) * time.Second)
 		dynamicClient, err := cmgr.factory.DynamicClient()
 		if err != nil {
 			return false, err
 		}
 		nameSelector := fields.OneTermEqualSelector("metadata.name", name).String()
-		gottenObjList, err := dynamicClient.Resource(gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{FieldSelector: nameSelector})
+		gottenObjList, err := dynamicClient.Resource(gvr).Namespace(ns).List(ctx, metav1.ListOptions{FieldSelector: nameSelector})
 		if apierrors.IsNotFound(err) {
 			gottenObjList = &unstructured.UnstructuredList{}
 		} else if err != nil && !apierrors.IsNotFound(err) {
@@ -67,7 +68,7 @@ func (cmgr clientMgr) WatchEventsForCondition(timeoutSecs int, ns, name string,
 		watchOptions := metav1.ListOptions{}
 		watchOptions.FieldSelector = nameSelector
 		watchOptions.ResourceVersion = gottenObjList.GetResourceVersion()
-		objWatch, err := dynamicClient.Resource(gvr).Namespace(ns).Watch(context.TODO(), watchOptions)
+		objWatch, err := dynamicClient.Resource(gvr).Namespace(ns).Watch(ctx, watchOptions)
 		if err != nil {
 			return false, err
 		}
@@ -84,9 +85,13 @@ func (cmgr clientMgr) WatchEventsForCondition(timeoutSecs int, ns, name string,
 			obj := event.Object.(*unstructured.Unstructured)
 			return condition(event, obj)
 		}
-		ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), timeout)
-		lastEvent, err := watchtools.UntilWithoutRetry(ctx, objWatch, isConditionMet)
+		watchCtx, cancel := watchtools.ContextWithOptionalTimeout(ctx, timeout)
+		lastEvent, err := watchtools.UntilWithoutRetry(watchCtx, objWatch, isConditionMet)
 		cancel()
+		// The watch stops with a timeout error when the parent context is cancelled too
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
 		if err == watchtools.ErrWatchClosed {
 			continue
 		}
@@ -98,19 +103,19 @@ func (cmgr clientMgr) WatchEventsForCondition(timeoutSecs int, ns, name string,
 }
 
 // WaitForResource waits until a resource is present in the k8s API
-func (cmgr clientMgr) WaitForResource(timeoutSecs int, ns, name string, gvr schema.GroupVersionResource) (bool, error) {
+func (cmgr clientMgr) WaitForResource(ctx context.Context, timeoutSecs int, ns, name string, gvr schema.GroupVersionResource) (bool, error) {
 	var condFunc ConditionFunction = func(event watch.Event, obj *unstructured.Unstructured) (bool, error) {
 		if event.Type == watch.Deleted {
 			return false, nil
 		}
 		return true, nil
 	}
-	return cmgr.WatchEventsForCondition(timeoutSecs, ns, name, gvr, condFunc)
+	return cmgr.WatchEventsForCondition(ctx, timeoutSecs, ns, name, gvr, condFunc)
 
 }
 
 // WaitForResourceStatusCondition waits until a resource is present in the k8s API with a given status.conditions
-func (cmgr clientMgr) WaitForResourceStatusCondition(timeoutSecs int, ns, name, conditionStr string, gvr schema.GroupVersionResource) (bool, error) {
+func (cmgr clientMgr) WaitForResourceStatusCondition(ctx context.Context, timeoutSecs int, ns, name, conditionStr string, gvr schema.GroupVersionResource) (bool, error) {
 	var condFunc ConditionFunction = func(event watch.Event, obj *unstructured.Unstructured) (bool, error) {
 		conditions, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
 		if err != nil {
@@ -134,12 +139,12 @@ func (cmgr clientMgr) WaitForResourceStatusCondition(timeoutSecs int, ns, name,
 
 		return false, nil
 	}
-	return cmgr.WatchEventsForCondition(timeoutSecs, ns, name, gvr, condFunc)
+	return cmgr.WatchEventsForCondition(ctx, timeoutSecs, ns, name, gvr, condFunc)
 
 }
 
 // WaitForResourceReplicas waits until a resource has the given number of replicas in "ready" state
-func (cmgr clientMgr) WaitForResourceReplicas(timeoutSecs int, ns, name, replicas string, gvr schema.GroupVersionResource) (bool, error) {
+func (cmgr clientMgr) WaitForResourceReplicas(ctx context.Context, timeoutSecs int, ns, name, replicas string, gvr schema.GroupVersionResource) (bool, error) {
 	var condFunc ConditionFunction = func(event watch.Event, obj *unstructured.Unstructured) (bool, error) {
 		readyReplicas, found, err := unstructured.NestedString(obj.Object, "status", "readyReplicas")
 		if err != nil {
@@ -150,5 +155,5 @@ func (cmgr clientMgr) WaitForResourceReplicas(timeoutSecs int, ns, name, replica
 		}
 		return strings.EqualFold(readyReplicas, replicas), nil
 	}
-	return cmgr.WatchEventsForCondition(timeoutSecs, ns, name, gvr, condFunc)
+	return cmgr.WatchEventsForCondition(ctx, timeoutSecs, ns, name, gvr, condFunc)
 }
@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// dynamicFactory only provides a dynamic client
+type dynamicFactory struct {
+	client dynamic.Interface
+}
+
+func (f dynamicFactory) StaticClient() (*kubernetes.Clientset, error) {
+	return nil, errors.New("not supported")
+}
+func (f dynamicFactory) DynamicClient() (dynamic.Interface, error) { return f.client, nil }
+func (f dynamicFactory) RestConfig() (*rest.Config, error)         { return nil, errors.New("not supported") }
+func (f dynamicFactory) GetOverrideFlags() (*genericclioptions.ConfigFlags, error) {
+	return nil, errors.New("not supported")
+}
+func (f dynamicFactory) Builder() *resource.Builder { return nil }
+
+// TestWatchEventsForConditionTimeout tests watches closed by the server don't extend the timeout
+func TestWatchEventsForConditionTimeout(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	// Every watch is closed right away, as when the API server ends watches
+	client.PrependWatchReactor("statefulsets", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFake()
+		w.Stop()
+		return true, w, nil
+	})
+	cmgr := clientMgr{factory: dynamicFactory{client: client}}
+	never := func(event watch.Event, obj *unstructured.Unstructured) (bool, error) { return false, nil }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	met, err := cmgr.WatchEventsForCondition(ctx, 1, "test", "ds-idrepo", gvr, never)
+	if met || !errors.Is(err, ErrWatchTimeout) {
+		t.Errorf("expected the wait to time out, found: %t, %+v", met, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the wait to stop after its timeout, found: %s", elapsed)
+	}
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	io "io"
 
 	factory "github.com/ForgeRock/forgeops-cli/internal/factory"
@@ -21,13 +23,13 @@ type ClientMgr struct {
 	mock.Mock
 }
 
-// ApplyObject provides a mock function with given fields: info
-func (_m *ClientMgr) ApplyObject(info *resource.Info) error {
-	ret := _m.Called(info)
+// ApplyObject provides a mock function with given fields: ctx, info, opts
+func (_m *ClientMgr) ApplyObject(ctx context.Context, info *resource.Info, opts k8s.ApplyOptions) error {
+	ret := _m.Called(ctx, info, opts)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*resource.Info) error); ok {
-		r0 = rf(info)
+	if rf, ok := ret.Get(0).(func(context.Context, *resource.Info, k8s.ApplyOptions) error); ok {
+		r0 = rf(ctx, info, opts)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -35,13 +37,13 @@ func (_m *ClientMgr) ApplyObject(info *resource.Info) error {
 	return r0
 }
 
-// DeleteObject provides a mock function with given fields: info
-func (_m *ClientMgr) DeleteObject(info *resource.Info) error {
-	ret := _m.Called(info)
+// DeleteObject provides a mock function with given fields: ctx, info
+func (_m *ClientMgr) DeleteObject(ctx context.Context, info *resource.Info) error {
+	ret := _m.Called(ctx, info)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*resource.Info) error); ok {
-		r0 = rf(info)
+	if rf, ok := ret.Get(0).(func(context.Context, *resource.Info) error); ok {
+		r0 = rf(ctx, info)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -65,13 +67,13 @@ func (_m *ClientMgr) Factory() factory.Factory {
 	return r0
 }
 
-// GetObjectsFromPath provides a mock function with given fields: path
-func (_m *ClientMgr) GetObjectsFromPath(path string) ([]*resource.Info, error) {
-	ret := _m.Called(path)
+// GetObjectsFromPath provides a mock function with given fields: path, opts
+func (_m *ClientMgr) GetObjectsFromPath(path string, opts k8s.PathOptions) ([]*resource.Info, error) {
+	ret := _m.Called(path, opts)
 
 	var r0 []*resource.Info
-	if rf, ok := ret.Get(0).(func(string) []*resource.Info); ok {
-		r0 = rf(path)
+	if rf, ok := ret.Get(0).(func(string, k8s.PathOptions) []*resource.Info); ok {
+		r0 = rf(path, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*resource.Info)
@@ -79,8 +81,8 @@ func (_m *ClientMgr) GetObjectsFromPath(path string) ([]*resource.Info, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(path)
+	if rf, ok := ret.Get(1).(func(string, k8s.PathOptions) error); ok {
+		r1 = rf(path, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,13 +90,13 @@ func (_m *ClientMgr) GetObjectsFromPath(path string) ([]*resource.Info, error) {
 	return r0, r1
 }
 
-// GetObjectsFromServer provides a mock function with given fields: resourceType, name
-func (_m *ClientMgr) GetObjectsFromServer(resourceType string, name string) ([]*resource.Info, error) {
-	ret := _m.Called(resourceType, name)
+// GetObjectsFromServer provides a mock function with given fields: ctx, resourceType, name
+func (_m *ClientMgr) GetObjectsFromServer(ctx context.Context, resourceType string, name string) ([]*resource.Info, error) {
+	ret := _m.Called(ctx, resourceType, name)
 
 	var r0 []*resource.Info
-	if rf, ok := ret.Get(0).(func(string, string) []*resource.Info); ok {
-		r0 = rf(resourceType, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*resource.Info); ok {
+		r0 = rf(ctx, resourceType, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*resource.Info)
@@ -102,8 +104,8 @@ func (_m *ClientMgr) GetObjectsFromServer(resourceType string, name string) ([]*
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(resourceType, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, resourceType, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -134,6 +136,43 @@ func (_m *ClientMgr) GetObjectsFromStream(reader io.Reader) ([]*resource.Info, e
 	return r0, r1
 }
 
+// ListObjects provides a mock function with given fields: ctx, ns, resourceType, labelSelector
+func (_m *ClientMgr) ListObjects(ctx context.Context, ns string, resourceType string, labelSelector string) ([]*resource.Info, error) {
+	ret := _m.Called(ctx, ns, resourceType, labelSelector)
+
+	var r0 []*resource.Info
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) []*resource.Info); ok {
+		r0 = rf(ctx, ns, resourceType, labelSelector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*resource.Info)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ns, resourceType, labelSelector)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MapObjects provides a mock function with given fields: infos
+func (_m *ClientMgr) MapObjects(infos []*resource.Info) error {
+	ret := _m.Called(infos)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*resource.Info) error); ok {
+		r0 = rf(infos)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Namespace provides a mock function with given fields:
 func (_m *ClientMgr) Namespace() (string, error) {
 	ret := _m.Called()
@@ -155,20 +194,34 @@ func (_m *ClientMgr) Namespace() (string, error) {
 	return r0, r1
 }
 
-// WaitForResource provides a mock function with given fields: timeoutSecs, ns, name, gvr
-func (_m *ClientMgr) WaitForResource(timeoutSecs int, ns string, name string, gvr schema.GroupVersionResource) (bool, error) {
-	ret := _m.Called(timeoutSecs, ns, name, gvr)
+// ResetRESTMapper provides a mock function with given fields:
+func (_m *ClientMgr) ResetRESTMapper() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WaitForResource provides a mock function with given fields: ctx, timeoutSecs, ns, name, gvr
+func (_m *ClientMgr) WaitForResource(ctx context.Context, timeoutSecs int, ns string, name string, gvr schema.GroupVersionResource) (bool, error) {
+	ret := _m.Called(ctx, timeoutSecs, ns, name, gvr)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, string, string, schema.GroupVersionResource) bool); ok {
-		r0 = rf(timeoutSecs, ns, name, gvr)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, schema.GroupVersionResource) bool); ok {
+		r0 = rf(ctx, timeoutSecs, ns, name, gvr)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int, string, string, schema.GroupVersionResource) error); ok {
-		r1 = rf(timeoutSecs, ns, name, gvr)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, schema.GroupVersionResource) error); ok {
+		r1 = rf(ctx, timeoutSecs, ns, name, gvr)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -176,20 +229,20 @@ func (_m *ClientMgr) WaitForResource(timeoutSecs int, ns string, name string, gv
 	return r0, r1
 }
 
-// WaitForResourceReplicas provides a mock function with given fields: timeoutSecs, ns, name, replicas, gvr
-func (_m *ClientMgr) WaitForResourceReplicas(timeoutSecs int, ns string, name string, replicas string, gvr schema.GroupVersionResource) (bool, error) {
-	ret := _m.Called(timeoutSecs, ns, name, replicas, gvr)
+// WaitForResourceReplicas provides a mock function with given fields: ctx, timeoutSecs, ns, name, replicas, gvr
+func (_m *ClientMgr) WaitForResourceReplicas(ctx context.Context, timeoutSecs int, ns string, name string, replicas string, gvr schema.GroupVersionResource) (bool, error) {
+	ret := _m.Called(ctx, timeoutSecs, ns, name, replicas, gvr)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, string, string, string, schema.GroupVersionResource) bool); ok {
-		r0 = rf(timeoutSecs, ns, name, replicas, gvr)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, schema.GroupVersionResource) bool); ok {
+		r0 = rf(ctx, timeoutSecs, ns, name, replicas, gvr)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int, string, string, string, schema.GroupVersionResource) error); ok {
-		r1 = rf(timeoutSecs, ns, name, replicas, gvr)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, string, schema.GroupVersionResource) error); ok {
+		r1 = rf(ctx, timeoutSecs, ns, name, replicas, gvr)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -197,20 +250,20 @@ func (_m *ClientMgr) WaitForResourceReplicas(timeoutSecs int, ns string, name st
 	return r0, r1
 }
 
-// WaitForResourceStatusCondition provides a mock function with given fields: timeoutSecs, ns, name, conditionStr, gvr
-func (_m *ClientMgr) WaitForResourceStatusCondition(timeoutSecs int, ns string, name string, conditionStr string, gvr schema.GroupVersionResource) (bool, error) {
-	ret := _m.Called(timeoutSecs, ns, name, conditionStr, gvr)
+// WaitForResourceStatusCondition provides a mock function with given fields: ctx, timeoutSecs, ns, name, conditionStr, gvr
+func (_m *ClientMgr) WaitForResourceStatusCondition(ctx context.Context, timeoutSecs int, ns string, name string, conditionStr string, gvr schema.GroupVersionResource) (bool, error) {
+	ret := _m.Called(ctx, timeoutSecs, ns, name, conditionStr, gvr)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, string, string, string, schema.GroupVersionResource) bool); ok {
-		r0 = rf(timeoutSecs, ns, name, conditionStr, gvr)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, string, schema.GroupVersionResource) bool); ok {
+		r0 = rf(ctx, timeoutSecs, ns, name, conditionStr, gvr)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int, string, string, string, schema.GroupVersionResource) error); ok {
-		r1 = rf(timeoutSecs, ns, name, conditionStr, gvr)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, string, schema.GroupVersionResource) error); ok {
+		r1 = rf(ctx, timeoutSecs, ns, name, conditionStr, gvr)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -218,20 +271,20 @@ func (_m *ClientMgr) WaitForResourceStatusCondition(timeoutSecs int, ns string,
 	return r0, r1
 }
 
-// WatchEventsForCondition provides a mock function with given fields: timeoutSecs, ns, name, gvr, condition
-func (_m *ClientMgr) WatchEventsForCondition(timeoutSecs int, ns string, name string, gvr schema.GroupVersionResource, condition k8s.ConditionFunction) (bool, error) {
-	ret := _m.Called(timeoutSecs, ns, name, gvr, condition)
+// WatchEventsForCondition provides a mock function with given fields: ctx, timeoutSecs, ns, name, gvr, condition
+func (_m *ClientMgr) WatchEventsForCondition(ctx context.Context, timeoutSecs int, ns string, name string, gvr schema.GroupVersionResource, condition k8s.ConditionFunction) (bool, error) {
+	ret := _m.Called(ctx, timeoutSecs, ns, name, gvr, condition)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(int, string, string, schema.GroupVersionResource, k8s.ConditionFunction) bool); ok {
-		r0 = rf(timeoutSecs, ns, name, gvr, condition)
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, string, schema.GroupVersionResource, k8s.ConditionFunction) bool); ok {
+		r0 = rf(ctx, timeoutSecs, ns, name, gvr, condition)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int, string, string, schema.GroupVersionResource, k8s.ConditionFunction) error); ok {
-		r1 = rf(timeoutSecs, ns, name, gvr, condition)
+	if rf, ok := ret.Get(1).(func(context.Context, int, string, string, schema.GroupVersionResource, k8s.ConditionFunction) error); ok {
+		r1 = rf(ctx, timeoutSecs, ns, name, gvr, condition)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2,6 +2,7 @@ package printer
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -57,6 +58,17 @@ func Println(s string) {
 	console.Printf(fmtStr, regColorPrefix(regStr), regColorMsg(s))
 }
 
+// SetConsoleOutput sets where the notices, warnings and errors are written. Commands whose stdout
+// carries documents, e.g. the manifests of a client dry run, write them to stderr so stdout can be piped
+func SetConsoleOutput(out io.Writer) {
+	console = newConsole(out)
+}
+
+// Document print content as is, e.g. a rendered manifest
+func Document(s string) {
+	fmt.Fprintln(os.Stdout, s)
+}
+
 // Noticef print a notice message
 func Noticef(s string, args ...interface{}) {
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
@@ -118,6 +130,15 @@ func JsonResult(msg string, res *api.ForgeOpsResult) {
 		Dict("results", eventResult).Msg(msg)
 }
 
+// JsonDocument provide a message whose results are the structured value provided, e.g. a list of records.
+// Like JsonResult, it's the "return value" of a command and ignores the log levels set by a user
+func JsonDocument(msg string, v interface{}) {
+	cmdResultOut.Info().
+		Str("version", "v1alpha1").
+		Str("status", string(api.ResultStatusSuccess)).
+		Interface("results", v).Msg(msg)
+}
+
 // Logger global main logger that should be used to log errors, debug etc
 func Logger() zerolog.Logger {
 	return logn
@@ -148,7 +169,18 @@ func init() {
 	zerolog.TimeFieldFormat = time.RFC3339
 
 	// Setup logging to console
-	consoleOutput := zerolog.ConsoleWriter{Out: os.Stdout}
+	console = newConsole(os.Stdout)
+
+	// Setup a command result log, this should be used to log the result of a command
+	cmdResultOut = zerolog.New(os.Stdout).
+		With().
+		Timestamp().
+		Logger()
+}
+
+// newConsole creates the logger printing the messages of the commands to out
+func newConsole(out io.Writer) zerolog.Logger {
+	consoleOutput := zerolog.ConsoleWriter{Out: out}
 	consoleOutput.FormatLevel = func(i interface{}) string {
 		return ""
 	}
@@ -164,11 +196,5 @@ func init() {
 	consoleOutput.PartsOrder = []string{
 		zerolog.MessageFieldName,
 	}
-	console = zerolog.New(consoleOutput)
-
-	// Setup a command result log, this should be used to log the result of a command
-	cmdResultOut = zerolog.New(os.Stdout).
-		With().
-		Timestamp().
-		Logger()
+	return zerolog.New(consoleOutput)
 }
@@ -0,0 +1,102 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	// ProgressRefresh interval the progress is updated in place on a terminal
+	ProgressRefresh = time.Second
+	// ProgressInterval interval the progress is printed when the output isn't a terminal or is json
+	ProgressInterval = 30 * time.Second
+)
+
+// Progress reports the progress of a long running operation, e.g. a wait.
+// The progress line is updated in place on stderr when it's a terminal, so the output of the command stays intact,
+// and printed periodically otherwise
+type Progress struct {
+	title       string
+	start       time.Time
+	timeout     time.Duration
+	status      func() string
+	interactive bool
+	stop        chan struct{}
+	wg          sync.WaitGroup
+	once        sync.Once
+}
+
+// StartProgress starts reporting the progress of the operation described by title.
+// status provides the current state of the operation, e.g. the ready replicas
+func StartProgress(title string, timeout time.Duration, status func() string) *Progress {
+	p := &Progress{
+		title:       title,
+		start:       time.Now(),
+		timeout:     timeout,
+		status:      status,
+		interactive: CommandOut == OutText && isatty.IsTerminal(os.Stderr.Fd()),
+		stop:        make(chan struct{}),
+	}
+	interval := ProgressInterval
+	if p.interactive {
+		interval = ProgressRefresh
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.render()
+			}
+		}
+	}()
+	return p
+}
+
+// Stop stops reporting the progress. The progress line is completed on a terminal
+func (p *Progress) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+		p.wg.Wait()
+		if p.interactive {
+			p.render()
+			fmt.Fprintln(os.Stderr)
+		}
+	})
+}
+
+func (p *Progress) render() {
+	line := progressLine(p.title, p.status(), time.Since(p.start), p.timeout)
+	if p.interactive {
+		// Return to the start of the line and clear it
+		fmt.Fprintf(os.Stderr, "\r\x1b[K%s %s", noticeColorPrefix(infoStr), noticeColorMsg(line))
+		return
+	}
+	Noticeln(line)
+}
+
+// progressLine describes the progress of an operation, e.g. am: ready 0/1 [1m0s elapsed, 9m0s left]
+func progressLine(title, status string, elapsed, timeout time.Duration) string {
+	line := title
+	if len(status) > 0 {
+		line += ": " + status
+	}
+	elapsed = elapsed.Round(time.Second)
+	if timeout <= 0 {
+		return fmt.Sprintf("%s [%s elapsed]", line, elapsed)
+	}
+	remaining := timeout - elapsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	return fmt.Sprintf("%s [%s elapsed, %s left]", line, elapsed, remaining.Round(time.Second))
+}
@@ -0,0 +1,169 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// validatorsSuffix suffix of the files keeping the validators of the cached "latest" manifests
+const validatorsSuffix = ".validators.json"
+
+// conditionalSource is a source able to skip downloads of manifests that didn't change
+type conditionalSource interface {
+	Source
+	// FetchIfModified returns utils.ErrNotModified when fileName matches the validators provided
+	FetchIfModified(ctx context.Context, ghRepo, version, fileName string, v utils.Validators) (string, utils.Validators, error)
+}
+
+// evictingSource is a source able to forget the files it obtained, e.g. a manifest that failed verification
+type evictingSource interface {
+	// Evict removes fileName so the next fetch obtains it again
+	Evict(ghRepo, version, fileName string)
+}
+
+// cachingSource keeps the manifests obtained from another source on disk, keyed by repo, tag and file.
+// Manifests of pinned tags never change and are downloaded once. Manifests of "latest" are revalidated with
+// conditional requests, and the cached copy is used when the source can't be reached
+type cachingSource struct {
+	source conditionalSource
+	dir    string
+}
+
+// NewCachedGitHub returns a source caching GitHub release manifests in the user cache dir.
+// Manifests aren't cached when there's no user cache dir
+func NewCachedGitHub() Source {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return GitHub{}
+	}
+	return newCachingSource(GitHub{}, filepath.Join(dir, "forgeops-cli", "releases"))
+}
+
+func newCachingSource(s conditionalSource, dir string) *cachingSource {
+	return &cachingSource{source: s, dir: dir}
+}
+
+// Fetch returns the cached contents of fileName, downloading it when needed
+func (c *cachingSource) Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	path, ok := c.path(ghRepo, version, fileName)
+	if !ok {
+		return c.source.Fetch(ctx, ghRepo, version, fileName)
+	}
+	cached, cacheErr := ioutil.ReadFile(path)
+	if normalizeVersion(version) != "latest" {
+		if cacheErr == nil {
+			return string(cached), nil
+		}
+		contents, err := c.source.Fetch(ctx, ghRepo, version, fileName)
+		if err != nil {
+			return "", err
+		}
+		c.store(path, contents, nil)
+		return contents, nil
+	}
+
+	validators := utils.Validators{}
+	if cacheErr == nil {
+		validators = readValidators(path + validatorsSuffix)
+	}
+	contents, newValidators, err := c.source.FetchIfModified(ctx, ghRepo, version, fileName, validators)
+	switch {
+	case errors.Is(err, utils.ErrNotModified) && cacheErr == nil:
+		return string(cached), nil
+	case errors.Is(err, utils.ErrNotFound):
+		return "", err
+	case err != nil && cacheErr == nil:
+		printer.Warnf("Couldn't check for updates of %s: %s. Using the cached copy", c.source.Location(ghRepo, version, fileName), err)
+		return string(cached), nil
+	case err != nil:
+		return "", err
+	}
+	c.store(path, contents, &newValidators)
+	return contents, nil
+}
+
+// Evict removes fileName and its validators from the cache so the next fetch downloads it again
+func (c *cachingSource) Evict(ghRepo, version, fileName string) {
+	path, ok := c.path(ghRepo, version, fileName)
+	if !ok {
+		return
+	}
+	for _, p := range []string{path, path + validatorsSuffix} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			printer.Warnf("Couldn't remove %s from the cache: %s", p, err)
+		}
+	}
+}
+
+// Location describes where fileName is obtained from
+func (c *cachingSource) Location(ghRepo, version, fileName string) string {
+	return c.source.Location(ghRepo, version, fileName)
+}
+
+// path returns the location of fileName in the cache. Names escaping the cache dir aren't cached
+func (c *cachingSource) path(ghRepo, version, fileName string) (string, bool) {
+	path := filepath.Join(c.dir, filepath.FromSlash(BundlePath(ghRepo, version, fileName)))
+	if !strings.HasPrefix(path, filepath.Clean(c.dir)+string(filepath.Separator)) {
+		return "", false
+	}
+	return path, true
+}
+
+// store writes the manifest and its validators to the cache. The cache is best effort, failures are only reported
+func (c *cachingSource) store(path, contents string, v *utils.Validators) {
+	if err := writeAtomic(path, []byte(contents)); err != nil {
+		printer.Warnf("Couldn't cache %s: %s", path, err)
+		return
+	}
+	if v == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = writeAtomic(path+validatorsSuffix, data)
+	}
+	if err != nil {
+		printer.Warnf("Couldn't cache %s: %s", path+validatorsSuffix, err)
+	}
+}
+
+func readValidators(path string) utils.Validators {
+	v := utils.Validators{}
+	if data, err := ioutil.ReadFile(path); err == nil {
+		// Invalid validators only result in a full download
+		_ = json.Unmarshal(data, &v)
+	}
+	return v
+}
+
+// writeAtomic writes the file through a temporary file so concurrent runs never read partial manifests
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
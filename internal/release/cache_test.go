@@ -0,0 +1,187 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// countingSource serves a single manifest and counts the requests made
+type countingSource struct {
+	contents   string
+	etag       string
+	err        error
+	requests   int
+	validators []utils.Validators
+}
+
+func (s *countingSource) Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	contents, _, err := s.FetchIfModified(ctx, ghRepo, version, fileName, utils.Validators{})
+	return contents, err
+}
+
+func (s *countingSource) FetchIfModified(ctx context.Context, ghRepo, version, fileName string, v utils.Validators) (string, utils.Validators, error) {
+	s.requests++
+	s.validators = append(s.validators, v)
+	if s.err != nil {
+		return "", utils.Validators{}, s.err
+	}
+	if len(v.ETag) > 0 && v.ETag == s.etag {
+		return "", v, utils.ErrNotModified
+	}
+	return s.contents, utils.Validators{ETag: s.etag}, nil
+}
+
+func (s *countingSource) Location(ghRepo, version, fileName string) string {
+	return fileName
+}
+
+func newTestCache(t *testing.T, s conditionalSource) *cachingSource {
+	dir, err := ioutil.TempDir("", "forgeops-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return newCachingSource(s, dir)
+}
+
+func fetchExpecting(t *testing.T, c *cachingSource, version, expected string) {
+	t.Helper()
+	contents, err := c.Fetch(context.Background(), "ForgeRock/forgeops", version, "base.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contents != expected {
+		t.Errorf("expected: %q, found: %q", expected, contents)
+	}
+}
+
+// TestCachePinnedTag tests manifests of pinned tags are downloaded once
+func TestCachePinnedTag(t *testing.T) {
+	s := &countingSource{contents: "v1"}
+	c := newTestCache(t, s)
+	fetchExpecting(t, c, "2020.10.28-AlSugoDiNoci", "v1")
+	s.contents, s.err = "v2", errors.New("offline")
+	fetchExpecting(t, c, "2020.10.28-AlSugoDiNoci", "v1")
+	if s.requests != 1 {
+		t.Errorf("expected 1 request, found: %d", s.requests)
+	}
+}
+
+// TestCacheLatest tests manifests of latest are revalidated
+func TestCacheLatest(t *testing.T) {
+	s := &countingSource{contents: "v1", etag: `"1"`}
+	c := newTestCache(t, s)
+	fetchExpecting(t, c, "latest", "v1")
+	// Not modified
+	fetchExpecting(t, c, "", "v1")
+	if s.validators[1].ETag != `"1"` {
+		t.Errorf("expected the cached ETag to be sent, found: %+v", s.validators[1])
+	}
+	// Modified
+	s.contents, s.etag = "v2", `"2"`
+	fetchExpecting(t, c, "latest", "v2")
+	// Offline
+	s.err = errors.New("offline")
+	fetchExpecting(t, c, "latest", "v2")
+	// Removed from the release
+	s.err = utils.ErrNotFound
+	if _, err := c.Fetch(context.Background(), "ForgeRock/forgeops", "latest", "base.yaml"); !errors.Is(err, utils.ErrNotFound) {
+		t.Errorf("expected error: %+v, found: %+v", utils.ErrNotFound, err)
+	}
+	if s.requests != 5 {
+		t.Errorf("expected 5 requests, found: %d", s.requests)
+	}
+}
+
+// TestCachePath tests file names escaping the cache aren't cached
+func TestCachePath(t *testing.T) {
+	c := newCachingSource(&countingSource{}, "/cache")
+	if _, ok := c.path("ForgeRock/forgeops", "latest", "../../../../etc/passwd"); ok {
+		t.Errorf("expected names escaping the cache not to be cached")
+	}
+	if path, ok := c.path("ForgeRock/forgeops", "", "base.yaml"); !ok || path != "/cache/ForgeRock/forgeops/latest/base.yaml" {
+		t.Errorf("unexpected cache path: %q", path)
+	}
+}
+
+// releaseSource serves the files of a release, the first downloads of each file can be corrupted
+type releaseSource struct {
+	files map[string]string
+	// corrupted number of corrupted downloads left by file
+	corrupted map[string]int
+	requests  map[string]int
+}
+
+func (s *releaseSource) Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	contents, _, err := s.FetchIfModified(ctx, ghRepo, version, fileName, utils.Validators{})
+	return contents, err
+}
+
+func (s *releaseSource) FetchIfModified(ctx context.Context, ghRepo, version, fileName string, v utils.Validators) (string, utils.Validators, error) {
+	s.requests[fileName]++
+	contents, ok := s.files[fileName]
+	if !ok {
+		return "", utils.Validators{}, utils.ErrNotFound
+	}
+	if s.corrupted[fileName] > 0 {
+		s.corrupted[fileName]--
+		return contents + "corrupted", utils.Validators{}, nil
+	}
+	return contents, utils.Validators{}, nil
+}
+
+func (s *releaseSource) Location(ghRepo, version, fileName string) string {
+	return fileName
+}
+
+// TestCacheVerification tests manifests failing verification aren't kept in the cache
+func TestCacheVerification(t *testing.T) {
+	base := "kind: ConfigMap\n"
+	s := &releaseSource{
+		files: map[string]string{
+			"base.yaml":  base,
+			ChecksumFile: sha256Hex(base) + "  base.yaml\n",
+		},
+		corrupted: map[string]int{"base.yaml": 1},
+		requests:  map[string]int{},
+	}
+	c := newTestCache(t, s)
+	v := NewVerifyingSource(c, nil)
+
+	// A corrupted download is downloaded again
+	contents, err := v.Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "base.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contents != base || s.requests["base.yaml"] != 2 {
+		t.Errorf("expected the manifest to be downloaded again, found: %q after %d requests", contents, s.requests["base.yaml"])
+	}
+	// The verified copy is cached
+	if _, err := NewVerifyingSource(c, nil).Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "base.yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if s.requests["base.yaml"] != 2 {
+		t.Errorf("expected the verified manifest to be cached, found: %d requests", s.requests["base.yaml"])
+	}
+
+	// Downloads failing verification twice aren't cached
+	s.corrupted["ds.yaml"] = 2
+	s.files["ds.yaml"] = "kind: Secret\n"
+	s.files[ChecksumFile] += sha256Hex("kind: Secret\n") + "  ds.yaml\n"
+	if _, err := NewVerifyingSource(c, nil).Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "ds.yaml"); !errors.Is(err, ErrVerification) {
+		t.Fatalf("expected the corrupted manifest to fail verification, found: %+v", err)
+	}
+	path, _ := c.path("ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "ds.yaml")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the corrupted manifest not to be cached, found: %+v", err)
+	}
+	// The next install downloads it again
+	if _, err := NewVerifyingSource(c, nil).Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "ds.yaml"); err != nil {
+		t.Errorf("expected the manifest to be downloaded again, found: %+v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// DefaultGitHubAPIURL API of github.com
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// enterpriseAPIPath path of the API of GitHub Enterprise servers
+const enterpriseAPIPath = "/api/v3"
+
+// gitHubAPI API the releases are obtained from. With assetsThroughAPI the release assets are downloaded through the
+// API instead of the download URLs of the releases, which only serve the assets of public repos
+var gitHubAPI = struct {
+	mu               sync.Mutex
+	URL              string
+	assetsThroughAPI bool
+	// releases obtained from the API by repo and tag
+	releases map[string]ghRelease
+}{URL: DefaultGitHubAPIURL, releases: map[string]ghRelease{}}
+
+// ConfigureGitHub selects the API the releases are obtained from, e.g. https://github.example.com/api/v3 for a
+// GitHub Enterprise server. With assetsThroughAPI the release assets are downloaded through the API, as required
+// for the assets of private repos
+func ConfigureGitHub(apiURL string, assetsThroughAPI bool) error {
+	u, err := url.Parse(apiURL)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return fmt.Errorf("invalid GitHub API URL %q. Expected e.g. https://github.example.com%s", apiURL, enterpriseAPIPath)
+	}
+	gitHubAPI.mu.Lock()
+	defer gitHubAPI.mu.Unlock()
+	gitHubAPI.URL = strings.TrimSuffix(apiURL, "/")
+	gitHubAPI.assetsThroughAPI = assetsThroughAPI
+	gitHubAPI.releases = map[string]ghRelease{}
+	return nil
+}
+
+// apiURL returns the URL of the API and whether the assets are downloaded through it
+func apiURL() (string, bool) {
+	gitHubAPI.mu.Lock()
+	defer gitHubAPI.mu.Unlock()
+	return gitHubAPI.URL, gitHubAPI.assetsThroughAPI
+}
+
+// webURL returns the URL of the server hosting the API, e.g. https://github.com for https://api.github.com
+func webURL() string {
+	api, _ := apiURL()
+	if api == DefaultGitHubAPIURL {
+		return "https://github.com"
+	}
+	return strings.TrimSuffix(api, enterpriseAPIPath)
+}
+
+// apiRelease obtains the release of ghRepo tagged with version from the API once
+func apiRelease(ctx context.Context, ghRepo, version string) (ghRelease, error) {
+	api, _ := apiURL()
+	key := ghRepo + "/" + version
+	gitHubAPI.mu.Lock()
+	r, ok := gitHubAPI.releases[key]
+	gitHubAPI.mu.Unlock()
+	if ok {
+		return r, nil
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/releases/tags/%s", api, ghRepo, url.PathEscape(version))
+	if version == "latest" {
+		endpoint = fmt.Sprintf("%s/repos/%s/releases/latest", api, ghRepo)
+	}
+	contents, err := utils.DownloadTextFile(ctx, endpoint)
+	if err != nil {
+		return ghRelease{}, err
+	}
+	if err := json.Unmarshal([]byte(contents), &r); err != nil {
+		return ghRelease{}, fmt.Errorf("error reading release %q of %q: %w", version, ghRepo, err)
+	}
+	gitHubAPI.mu.Lock()
+	gitHubAPI.releases[key] = r
+	gitHubAPI.mu.Unlock()
+	return r, nil
+}
+
+// fetchAsset downloads fileName through the API unless it matches the validators provided
+func fetchAsset(ctx context.Context, ghRepo, version, fileName string, v utils.Validators) (string, utils.Validators, error) {
+	r, err := apiRelease(ctx, ghRepo, version)
+	if err != nil {
+		return "", utils.Validators{}, err
+	}
+	for _, a := range r.Assets {
+		if a.Name == fileName {
+			return utils.DownloadAssetIfModified(ctx, a.URL, v)
+		}
+	}
+	return "", utils.Validators{}, fmt.Errorf("%w: %s isn't an asset of release %q of %q", utils.ErrNotFound, fileName, r.TagName, ghRepo)
+}
@@ -0,0 +1,84 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// configureTestGitHub downloads the releases through the API of a test server until the test completes
+func configureTestGitHub(t *testing.T, handler http.Handler) {
+	server := httptest.NewServer(handler)
+	if err := ConfigureGitHub(server.URL+enterpriseAPIPath, true); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		server.Close()
+		ConfigureGitHub(DefaultGitHubAPIURL, false)
+	})
+}
+
+// TestGitHubAPI tests the release assets are downloaded through the API asset endpoint
+func TestGitHubAPI(t *testing.T) {
+	var accept string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/ForgeRock/forgeops/releases/tags/2020.10.28-AlSugoDiNoci", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": "2020.10.28-AlSugoDiNoci", "assets": [{"name": "ds.yaml", "url": "http://%s/api/v3/repos/ForgeRock/forgeops/releases/assets/1"}]}`, r.Host)
+	})
+	mux.HandleFunc("/api/v3/repos/ForgeRock/forgeops/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "2020.10.28-AlSugoDiNoci"}`)
+	})
+	mux.HandleFunc("/api/v3/repos/ForgeRock/forgeops/releases/assets/1", func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		fmt.Fprint(w, "kind: DirectoryService")
+	})
+	configureTestGitHub(t, mux)
+
+	contents, err := GitHub{}.Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "ds.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contents != "kind: DirectoryService" {
+		t.Errorf("expected the contents of the asset, found: %q", contents)
+	}
+	if accept != "application/octet-stream" {
+		t.Errorf("expected the asset to be requested as application/octet-stream, found: %q", accept)
+	}
+	if _, err := (GitHub{}).Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "am.yaml"); !errors.Is(err, utils.ErrNotFound) {
+		t.Errorf("expected missing assets not to be found, found: %+v", err)
+	}
+	tag, err := GitHub{}.ResolveLatest(context.Background(), "ForgeRock/forgeops")
+	if err != nil || tag != "2020.10.28-AlSugoDiNoci" {
+		t.Errorf("expected the tag of the latest release, found: %q, %+v", tag, err)
+	}
+}
+
+// TestWebURL tests the download URLs of the releases are on the server hosting the API
+func TestWebURL(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		apiURL      string
+		expected    string
+	}{
+		{testComment: "github.com", apiURL: DefaultGitHubAPIURL, expected: "https://github.com/ForgeRock/forgeops/releases/latest/download/ds.yaml"},
+		{testComment: "GitHub Enterprise", apiURL: "https://github.example.com/api/v3/", expected: "https://github.example.com/ForgeRock/forgeops/releases/latest/download/ds.yaml"},
+	}
+	t.Cleanup(func() { ConfigureGitHub(DefaultGitHubAPIURL, false) })
+	for _, tc := range td {
+		if err := ConfigureGitHub(tc.apiURL, false); err != nil {
+			t.Fatal(err)
+		}
+		if found := URL("ForgeRock/forgeops", "latest", "ds.yaml"); found != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, found)
+		}
+	}
+	if err := ConfigureGitHub("github.example.com", false); err == nil {
+		t.Errorf("expected API URLs without a scheme to be rejected")
+	}
+}
@@ -0,0 +1,243 @@
+package release
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// BundlePath returns the path of a release manifest inside a manifest directory or bundle.
+// Manifests are laid out as <owner>/<repo>/<tag>/<file>, e.g. ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/base.yaml
+func BundlePath(ghRepo, version, fileName string) string {
+	return path.Join(ghRepo, normalizeVersion(version), fileName)
+}
+
+// localFiles provides read access to a tree of manifests
+type localFiles interface {
+	// read returns the contents of the file. The bool is false if the file doesn't exist
+	read(name string) (string, bool, error)
+	// tags returns the release tags available for the given repo
+	tags(ghRepo string) ([]string, error)
+}
+
+// localSource obtains manifests from a local directory or bundle instead of GitHub.
+// A manifest is looked up in the following order:
+// 1. <owner>/<repo>/<tag>/<file>
+// 2. if the tag is "latest", <owner>/<repo>/<newest tag>/<file>
+// 3. if the tag is "latest" or the repo has no tags in the tree, <file> at the root of the tree
+type localSource struct {
+	description string
+	files       localFiles
+}
+
+// Fetch returns the contents of the manifest
+func (s *localSource) Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	name, err := s.resolve(ghRepo, version, fileName)
+	if err != nil {
+		return "", err
+	}
+	contents, _, err := s.files.read(name)
+	return contents, err
+}
+
+// Location returns the path of the manifest in the local tree
+func (s *localSource) Location(ghRepo, version, fileName string) string {
+	name, err := s.resolve(ghRepo, version, fileName)
+	if err != nil {
+		name = BundlePath(ghRepo, version, fileName)
+	}
+	return fmt.Sprintf("%s:%s", s.description, name)
+}
+
+func (s *localSource) resolve(ghRepo, version, fileName string) (string, error) {
+	version = normalizeVersion(version)
+	tags, err := s.files.tags(ghRepo)
+	if err != nil {
+		return "", err
+	}
+	candidates := []string{BundlePath(ghRepo, version, fileName)}
+	if version == "latest" {
+		if tag, ok := newestTag(tags); ok {
+			candidates = append(candidates, BundlePath(ghRepo, tag, fileName))
+		}
+	}
+	// The manifests at the root aren't of any particular tag. They don't stand for tags missing from the tree
+	if version == "latest" || len(tags) == 0 {
+		candidates = append(candidates, fileName)
+	}
+	for _, name := range candidates {
+		_, found, err := s.files.read(name)
+		if err != nil {
+			return "", err
+		}
+		if found {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q version %q of %q in %s", utils.ErrNotFound, fileName, version, ghRepo, s.description)
+}
+
+var tagParts = regexp.MustCompile(`\d+|\D+`)
+
+// newestTag returns the newest of the release tags, ignoring a "latest" directory.
+// The numbers in the tags are compared by value, e.g. v0.10.0 is newer than v0.9.1
+func newestTag(tags []string) (string, bool) {
+	newest := ""
+	for _, tag := range tags {
+		if tag != "latest" && (len(newest) == 0 || newerTag(tag, newest)) {
+			newest = tag
+		}
+	}
+	return newest, len(newest) > 0
+}
+
+// newerTag reports whether tag a is newer than tag b
+func newerTag(a, b string) bool {
+	partsA, partsB := tagParts.FindAllString(a, -1), tagParts.FindAllString(b, -1)
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		pa, pb := partsA[i], partsB[i]
+		if pa == pb {
+			continue
+		}
+		if isDigits(pa) && isDigits(pb) {
+			pa, pb = strings.TrimLeft(pa, "0"), strings.TrimLeft(pb, "0")
+			if len(pa) != len(pb) {
+				return len(pa) > len(pb)
+			}
+		}
+		return pa > pb
+	}
+	return len(partsA) > len(partsB)
+}
+
+func isDigits(s string) bool {
+	return len(s) > 0 && s[0] >= '0' && s[0] <= '9'
+}
+
+// NewDirSource creates a source that reads manifests from a local directory
+func NewDirSource(dir string) (Source, error) {
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("%q is not a directory", dir)
+	}
+	return &localSource{
+		description: dir,
+		files:       dirFiles(dir),
+	}, nil
+}
+
+type dirFiles string
+
+func (d dirFiles) read(name string) (string, bool, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(name)))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(contents), true, nil
+}
+
+func (d dirFiles) tags(ghRepo string) ([]string, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(string(d), filepath.FromSlash(ghRepo)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			tags = append(tags, entry.Name())
+		}
+	}
+	return tags, nil
+}
+
+// NewBundleSource creates a source that reads manifests from a .tar.gz bundle
+func NewBundleSource(bundleFile string) (Source, error) {
+	f, err := os.Open(bundleFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	files, err := readBundle(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle %q: %w", bundleFile, err)
+	}
+	return &localSource{
+		description: bundleFile,
+		files:       files,
+	}, nil
+}
+
+// bundleFiles holds the contents of a bundle in memory
+type bundleFiles map[string]string
+
+func readBundle(r io.Reader) (bundleFiles, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	files := bundleFiles{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		contents, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(strings.TrimPrefix(hdr.Name, "./"))] = string(contents)
+	}
+	return files, nil
+}
+
+func (b bundleFiles) read(name string) (string, bool, error) {
+	contents, found := b[name]
+	return contents, found, nil
+}
+
+func (b bundleFiles) tags(ghRepo string) ([]string, error) {
+	seen := map[string]bool{}
+	tags := []string{}
+	prefix := ghRepo + "/"
+	for name := range b {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(name, prefix), "/", 2)
+		if len(parts) != 2 || seen[parts[0]] {
+			continue
+		}
+		seen[parts[0]] = true
+		tags = append(tags, parts[0])
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
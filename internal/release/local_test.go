@@ -0,0 +1,159 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// newTestBundle builds a .tar.gz bundle from the given files
+func newTestBundle(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+// TestBundleSource tests the manifest lookup order of local sources
+func TestBundleSource(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		// files in the bundle
+		files map[string]string
+		// requested manifest
+		ghRepo, version, fileName string
+		// expected contents
+		expected string
+		// expected error
+		expectedError error
+	}{
+		{
+			testComment: "manifest of the requested tag is returned",
+			files: map[string]string{
+				"ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/base.yaml":    "tagged",
+				"ForgeRock/forgeops/2020.08.07-ZucchiniRicotta/base.yaml": "older",
+			},
+			ghRepo: "ForgeRock/forgeops", version: "2020.10.28-AlSugoDiNoci", fileName: "base.yaml",
+			expected: "tagged",
+		},
+		{
+			testComment: "latest resolves to the only tag in the bundle",
+			files: map[string]string{
+				"./ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/base.yaml": "tagged",
+			},
+			ghRepo: "ForgeRock/forgeops", version: "latest", fileName: "base.yaml",
+			expected: "tagged",
+		},
+		{
+			testComment: "flat manifests are used as a fallback",
+			files: map[string]string{
+				"base.yaml": "flat",
+			},
+			ghRepo: "ForgeRock/forgeops", version: "", fileName: "base.yaml",
+			expected: "flat",
+		},
+		{
+			testComment: "flat manifests stand for any tag of a tree without tags",
+			files: map[string]string{
+				"base.yaml": "flat",
+			},
+			ghRepo: "ForgeRock/forgeops", version: "2020.10.28-AlSugoDiNoci", fileName: "base.yaml",
+			expected: "flat",
+		},
+		{
+			testComment: "flat manifests don't stand for tags missing from a tree with tags",
+			files: map[string]string{
+				"ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/base.yaml": "tagged",
+				"base.yaml": "flat",
+			},
+			ghRepo: "ForgeRock/forgeops", version: "2020.08.07-ZucchiniRicotta", fileName: "base.yaml",
+			expectedError: utils.ErrNotFound,
+		},
+		{
+			testComment: "latest resolves to the newest of several tags",
+			files: map[string]string{
+				"ForgeRock/forgeops/2020.08.07-ZucchiniRicotta/base.yaml": "older",
+				"ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/base.yaml":    "newest",
+				"ForgeRock/forgeops/2020.09.12-Broccoli/base.yaml":        "old",
+			},
+			ghRepo: "ForgeRock/forgeops", version: "latest", fileName: "base.yaml",
+			expected: "newest",
+		},
+		{
+			testComment: "the numbers of the tags are compared by value",
+			files: map[string]string{
+				"ForgeRock/secret-agent/v0.9.1/secret-agent.yaml":  "older",
+				"ForgeRock/secret-agent/v0.10.0/secret-agent.yaml": "newest",
+			},
+			ghRepo: "ForgeRock/secret-agent", version: "latest", fileName: "secret-agent.yaml",
+			expected: "newest",
+		},
+		{
+			testComment: "missing manifests are not found",
+			files: map[string]string{
+				"ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/base.yaml": "tagged",
+			},
+			ghRepo: "ForgeRock/forgeops", version: "2020.08.07-ZucchiniRicotta", fileName: "base.yaml",
+			expectedError: utils.ErrNotFound,
+		},
+	}
+
+	for _, tc := range td {
+		files, err := readBundle(newTestBundle(t, tc.files))
+		if err != nil {
+			t.Fatal(err)
+		}
+		source := &localSource{description: "test.tar.gz", files: files}
+		contents, err := source.Fetch(context.Background(), tc.ghRepo, tc.version, tc.fileName)
+		if !errors.Is(err, tc.expectedError) {
+			t.Errorf("%s expected error: %+v, found: %+v", tc.testComment, tc.expectedError, err)
+		}
+		if contents != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, contents)
+		}
+	}
+}
+
+// TestNewestTag tests the tag "latest" is resolved to in a local tree
+func TestNewestTag(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		tags        []string
+		expected    string
+		expectFound bool
+	}{
+		{testComment: "dated tags", tags: []string{"2020.10.28-AlSugoDiNoci", "2020.08.07-ZucchiniRicotta"}, expected: "2020.10.28-AlSugoDiNoci", expectFound: true},
+		{testComment: "semantic versions", tags: []string{"v0.10.0", "v0.9.1", "v0.2.1"}, expected: "v0.10.0", expectFound: true},
+		{testComment: "patch releases", tags: []string{"v1.0.0", "v1.0.0.1"}, expected: "v1.0.0.1", expectFound: true},
+		{testComment: "a latest directory isn't a tag", tags: []string{"latest"}, expected: "", expectFound: false},
+		{testComment: "no tags", tags: nil, expected: "", expectFound: false},
+	}
+	for _, tc := range td {
+		tag, found := newestTag(tc.tags)
+		if found != tc.expectFound || tag != tc.expected {
+			t.Errorf("%s expected: %q, %t, found: %q, %t", tc.testComment, tc.expected, tc.expectFound, tag, found)
+		}
+	}
+}
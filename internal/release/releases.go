@@ -0,0 +1,86 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// GitHub repos publishing the manifests installed by the forgeops-cli
+const (
+	ForgeOpsRepo    = "ForgeRock/forgeops"
+	SecretAgentRepo = "ForgeRock/secret-agent"
+	DSOperatorRepo  = "ForgeRock/ds-operator"
+)
+
+// Repos publishing the manifests installed by the forgeops-cli
+var Repos = []string{ForgeOpsRepo, SecretAgentRepo, DSOperatorRepo}
+
+// Release describes a release published in GitHub
+type Release struct {
+	Tag         string    `json:"tag"`
+	Name        string    `json:"name,omitempty"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Prerelease  bool      `json:"prerelease,omitempty"`
+	Assets      []string  `json:"assets"`
+}
+
+// ghRelease fields of the GitHub releases API used
+type ghRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []struct {
+		Name string `json:"name"`
+		// URL API endpoint of the asset
+		URL string `json:"url"`
+	} `json:"assets"`
+}
+
+// List returns the most recent releases of ghRepo, newest first
+func List(ctx context.Context, ghRepo string, limit int) ([]Release, error) {
+	api, _ := apiURL()
+	contents, err := utils.DownloadTextFile(ctx, fmt.Sprintf("%s/repos/%s/releases?per_page=%d", api, ghRepo, limit))
+	if err != nil {
+		return nil, err
+	}
+	releases, err := parseReleases(contents)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the releases of %q: %w", ghRepo, err)
+	}
+	if len(releases) > limit {
+		releases = releases[:limit]
+	}
+	return releases, nil
+}
+
+// parseReleases reads the response of the GitHub releases API. Drafts are left out
+func parseReleases(contents string) ([]Release, error) {
+	ghReleases := []ghRelease{}
+	if err := json.Unmarshal([]byte(contents), &ghReleases); err != nil {
+		return nil, err
+	}
+	releases := []Release{}
+	for _, r := range ghReleases {
+		if r.Draft {
+			continue
+		}
+		assets := []string{}
+		for _, a := range r.Assets {
+			assets = append(assets, a.Name)
+		}
+		releases = append(releases, Release{
+			Tag:         r.TagName,
+			Name:        r.Name,
+			PublishedAt: r.PublishedAt,
+			Prerelease:  r.Prerelease,
+			Assets:      assets,
+		})
+	}
+	return releases, nil
+}
@@ -0,0 +1,32 @@
+package release
+
+import (
+	"testing"
+)
+
+// TestParseReleases tests the response of the GitHub releases API is read
+func TestParseReleases(t *testing.T) {
+	contents := `[
+  {"tag_name": "v1.1.0", "name": "", "draft": true, "prerelease": false, "published_at": null, "assets": []},
+  {"tag_name": "v1.0.0-rc1", "name": "RC", "draft": false, "prerelease": true, "published_at": "2020-10-28T10:00:00Z",
+   "assets": [{"name": "secret-agent.yaml"}, {"name": "SHA256SUMS"}]},
+  {"tag_name": "v0.2.1", "name": "v0.2.1", "draft": false, "prerelease": false, "published_at": "2020-08-07T10:00:00Z", "assets": []}
+]`
+	releases, err := parseReleases(contents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("expected drafts to be left out, found: %+v", releases)
+	}
+	if r := releases[0]; r.Tag != "v1.0.0-rc1" || !r.Prerelease || len(r.Assets) != 2 || r.Assets[0] != "secret-agent.yaml" ||
+		r.PublishedAt.Format("2006-01-02") != "2020-10-28" {
+		t.Errorf("unexpected release: %+v", r)
+	}
+	if r := releases[1]; r.Tag != "v0.2.1" || r.Prerelease || len(r.Assets) != 0 {
+		t.Errorf("unexpected release: %+v", r)
+	}
+	if _, err := parseReleases(`{"message": "API rate limit exceeded"}`); err == nil {
+		t.Error("expected error reading an API error")
+	}
+}
@@ -0,0 +1,131 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// latestResolver is a source able to tell which release tag "latest" stands for
+type latestResolver interface {
+	// ResolveLatest returns the tag of the latest release of ghRepo
+	ResolveLatest(ctx context.Context, ghRepo string) (string, error)
+}
+
+var resolvedMu sync.Mutex
+
+// resolved latest tag by repo. Resolved once per run so every component is installed from the same release
+var resolved = map[string]string{}
+
+// ResolveVersion returns the release tag "latest" stands for in the configured source.
+// Other versions are returned as they are. Sources unable to resolve "latest" keep it unresolved
+func ResolveVersion(ctx context.Context, ghRepo, version string) (string, error) {
+	version = normalizeVersion(version)
+	if version != "latest" {
+		return version, nil
+	}
+	resolvedMu.Lock()
+	defer resolvedMu.Unlock()
+	if tag, ok := resolved[ghRepo]; ok {
+		return tag, nil
+	}
+	resolver, ok := current.(latestResolver)
+	if !ok {
+		return version, nil
+	}
+	tag, err := resolver.ResolveLatest(ctx, ghRepo)
+	if err != nil {
+		return "", fmt.Errorf("error resolving the latest release of %q: %w", ghRepo, err)
+	}
+	if tag != version {
+		printer.NoticeHif("Resolved %q version: \"latest\" to %q", ghRepo, tag)
+	}
+	resolved[ghRepo] = tag
+	return tag, nil
+}
+
+// ResolveLatest follows the redirect of the latest release page to the page of its tag.
+// The latest release is obtained from the API when the assets are downloaded through it
+func (GitHub) ResolveLatest(ctx context.Context, ghRepo string) (string, error) {
+	if _, throughAPI := apiURL(); throughAPI {
+		r, err := apiRelease(ctx, ghRepo, "latest")
+		if err != nil {
+			return "", err
+		}
+		return r.TagName, nil
+	}
+	URL := fmt.Sprintf("%s/%s/releases/latest", webURL(), ghRepo)
+	location, err := utils.ResolveRedirect(ctx, URL)
+	if err != nil {
+		return "", err
+	}
+	return tagFromLocation(location)
+}
+
+// tagFromLocation extracts the tag of <server>/<owner>/<repo>/releases/tag/<tag>
+func tagFromLocation(location string) (string, error) {
+	const tagPath = "/releases/tag/"
+	i := strings.LastIndex(location, tagPath)
+	if i < 0 || len(location) == i+len(tagPath) {
+		// Repos without releases redirect to the list of releases
+		return "", fmt.Errorf("%w: no release tag in %s", utils.ErrNotFound, location)
+	}
+	return location[i+len(tagPath):], nil
+}
+
+// ResolveLatest resolves "latest" with the wrapped source
+func (v *verifyingSource) ResolveLatest(ctx context.Context, ghRepo string) (string, error) {
+	if resolver, ok := v.source.(latestResolver); ok {
+		return resolver.ResolveLatest(ctx, ghRepo)
+	}
+	return "latest", nil
+}
+
+// ResolveLatest resolves "latest" with the wrapped source. The last tag resolved is kept in the cache
+// and used when the source can't be reached
+func (c *cachingSource) ResolveLatest(ctx context.Context, ghRepo string) (string, error) {
+	resolver, ok := c.source.(latestResolver)
+	if !ok {
+		return "latest", nil
+	}
+	path, ok := c.path(ghRepo, "latest", ".tag")
+	if !ok {
+		return resolver.ResolveLatest(ctx, ghRepo)
+	}
+	tag, err := resolver.ResolveLatest(ctx, ghRepo)
+	if err == nil {
+		if err := writeAtomic(path, []byte(tag)); err != nil {
+			printer.Warnf("Couldn't cache %s: %s", path, err)
+		}
+		return tag, nil
+	}
+	cached, cacheErr := ioutil.ReadFile(path)
+	if cacheErr != nil || len(cached) == 0 {
+		return "", err
+	}
+	printer.Warnf("Couldn't resolve the latest release of %q: %s. Using the cached tag %q", ghRepo, err, string(cached))
+	return string(cached), nil
+}
+
+// ResolveLatest returns the newest tag of ghRepo in the local tree. "latest" is kept when the tree has no tags
+// or a "latest" directory
+func (s *localSource) ResolveLatest(ctx context.Context, ghRepo string) (string, error) {
+	tags, err := s.files.tags(ghRepo)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range tags {
+		if tag == "latest" {
+			return tag, nil
+		}
+	}
+	if tag, ok := newestTag(tags); ok {
+		return tag, nil
+	}
+	return "latest", nil
+}
@@ -0,0 +1,94 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// TestTagFromLocation tests the tag is read from the redirect of the latest release page
+func TestTagFromLocation(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		// redirect location
+		location string
+		// expected tag
+		expected string
+		// expected error
+		expectedError error
+	}{
+		{
+			testComment: "tag of the release page",
+			location:    "https://github.com/ForgeRock/forgeops/releases/tag/2020.10.28-AlSugoDiNoci",
+			expected:    "2020.10.28-AlSugoDiNoci",
+		},
+		{
+			testComment:   "repos without releases redirect to the releases",
+			location:      "https://github.com/ForgeRock/forgeops/releases",
+			expectedError: utils.ErrNotFound,
+		},
+	}
+
+	for _, tc := range td {
+		tag, err := tagFromLocation(tc.location)
+		if !errors.Is(err, tc.expectedError) {
+			t.Errorf("%s expected error: %+v, found: %+v", tc.testComment, tc.expectedError, err)
+		}
+		if tag != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, tag)
+		}
+	}
+}
+
+// TestResolveVersion tests latest is resolved once with the configured source
+func TestResolveVersion(t *testing.T) {
+	files, err := readBundle(newTestBundle(t, map[string]string{
+		"ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/base.yaml":        "tagged",
+		"ForgeRock/ds-operator/v0.0.8/ds-operator.yaml":               "older",
+		"ForgeRock/ds-operator/v0.1.0/ds-operator.yaml":               "newer",
+		"ForgeRock/secret-agent/v1.0.0/secret-agent.yaml":             "agent",
+		"ForgeRock/secret-agent/v1.0.0/" + ChecksumFile:               "",
+		"ForgeRock/forgeops/2020.10.28-AlSugoDiNoci/" + SignatureFile: "",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := current
+	defer func() {
+		current = previous
+		resolved = map[string]string{}
+	}()
+	SetSource(NewVerifyingSource(&localSource{description: "test.tar.gz", files: files}, nil))
+	resolved = map[string]string{}
+
+	td := []struct {
+		// comment about test case
+		testComment string
+		// requested release
+		ghRepo, version string
+		// expected tag
+		expected string
+	}{
+		{testComment: "latest resolves to the only tag", ghRepo: "ForgeRock/forgeops", version: "latest", expected: "2020.10.28-AlSugoDiNoci"},
+		{testComment: "empty version is latest", ghRepo: "ForgeRock/secret-agent", version: "", expected: "v1.0.0"},
+		{testComment: "latest resolves to the newest of several tags", ghRepo: "ForgeRock/ds-operator", version: "latest", expected: "v0.1.0"},
+		{testComment: "tags are kept", ghRepo: "ForgeRock/ds-operator", version: "v0.0.8", expected: "v0.0.8"},
+	}
+	for _, tc := range td {
+		tag, err := ResolveVersion(context.Background(), tc.ghRepo, tc.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tag != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, tag)
+		}
+	}
+	// Resolved once per run
+	SetSource(mapSource{})
+	if tag, _ := ResolveVersion(context.Background(), "ForgeRock/forgeops", "latest"); tag != "2020.10.28-AlSugoDiNoci" {
+		t.Errorf("expected the resolved tag to be reused, found: %q", tag)
+	}
+}
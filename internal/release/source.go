@@ -0,0 +1,87 @@
+package release
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// Source provides the contents of the manifests published with a release
+type Source interface {
+	// Fetch returns the contents of fileName published in the ghRepo release tagged with version
+	Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error)
+	// Location describes where fileName is obtained from
+	Location(ghRepo, version, fileName string) string
+}
+
+// current source used by Fetch. Defaults to verified GitHub releases, cached on disk
+var current Source = NewVerifyingSource(NewCachedGitHub(), nil)
+
+// SetSource replaces the source used to obtain release manifests
+func SetSource(s Source) {
+	current = s
+}
+
+// NewSource returns the source matching the given settings.
+// A manifest directory or a bundle file select a local source, GitHub releases cached on disk are used otherwise
+func NewSource(manifestDir, bundleFile string) (Source, error) {
+	switch {
+	case len(manifestDir) > 0 && len(bundleFile) > 0:
+		return nil, fmt.Errorf("--manifest-dir and --bundle are mutually exclusive")
+	case len(manifestDir) > 0:
+		return NewDirSource(manifestDir)
+	case len(bundleFile) > 0:
+		return NewBundleSource(bundleFile)
+	}
+	return NewCachedGitHub(), nil
+}
+
+// Fetch returns the contents of fileName from the configured source
+func Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	return current.Fetch(ctx, ghRepo, normalizeVersion(version), fileName)
+}
+
+// Location describes where fileName is obtained from by the configured source
+func Location(ghRepo, version, fileName string) string {
+	return current.Location(ghRepo, normalizeVersion(version), fileName)
+}
+
+// GitHub obtains manifests from the assets published in GitHub releases
+type GitHub struct{}
+
+// Fetch downloads the release asset
+func (g GitHub) Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	contents, _, err := g.FetchIfModified(ctx, ghRepo, version, fileName, utils.Validators{})
+	return contents, err
+}
+
+// FetchIfModified downloads the release asset unless it matches the validators of a previous download.
+// The assets are downloaded through the API when configured with ConfigureGitHub
+func (GitHub) FetchIfModified(ctx context.Context, ghRepo, version, fileName string, v utils.Validators) (string, utils.Validators, error) {
+	if _, throughAPI := apiURL(); throughAPI {
+		return fetchAsset(ctx, ghRepo, normalizeVersion(version), fileName, v)
+	}
+	return utils.DownloadTextFileIfModified(ctx, URL(ghRepo, version, fileName), v)
+}
+
+// Location returns the download URL of the release asset
+func (GitHub) Location(ghRepo, version, fileName string) string {
+	return URL(ghRepo, version, fileName)
+}
+
+// URL returns the download URL of a GitHub release asset
+func URL(ghRepo, version, fileName string) string {
+	version = normalizeVersion(version)
+	if version == "latest" {
+		return fmt.Sprintf("%s/%s/releases/latest/download/%s", webURL(), ghRepo, fileName)
+	}
+	return fmt.Sprintf("%s/%s/releases/download/%s/%s", webURL(), ghRepo, version, fileName)
+}
+
+func normalizeVersion(version string) string {
+	if len(version) == 0 {
+		return "latest"
+	}
+	return version
+}
@@ -0,0 +1,202 @@
+package release
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+)
+
+const (
+	// ChecksumFile sha256 checksums of the manifests published with every release
+	ChecksumFile = "SHA256SUMS"
+	// SignatureFile detached signature of the ChecksumFile
+	SignatureFile = "SHA256SUMS.sig"
+)
+
+// ErrVerification a manifest doesn't match the checksums or signature published with its release
+var ErrVerification = errors.New("verification failed")
+
+// verifyingSource verifies the manifests obtained from another source against the checksums published with the release
+type verifyingSource struct {
+	source    Source
+	publicKey crypto.PublicKey
+	mu        sync.Mutex
+	// checksums by repo and version
+	checksums map[string]map[string]string
+}
+
+// NewVerifyingSource returns a source verifying the manifests of the given source against the ChecksumFile of their
+// release. When a public key is provided, the ChecksumFile is verified against the SignatureFile as well
+func NewVerifyingSource(s Source, publicKey crypto.PublicKey) Source {
+	return &verifyingSource{
+		source:    s,
+		publicKey: publicKey,
+		checksums: map[string]map[string]string{},
+	}
+}
+
+// Fetch returns the contents of fileName once verified.
+// When the source keeps the files it obtained, e.g. the cache, the files that failed verification are obtained again once
+// and aren't kept when they fail again
+func (v *verifyingSource) Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	contents, err := v.fetch(ctx, ghRepo, version, fileName)
+	if !errors.Is(err, ErrVerification) {
+		return contents, err
+	}
+	e, ok := v.source.(evictingSource)
+	if !ok {
+		return "", err
+	}
+	printer.Warnf("%s. Downloading %s again", err, fileName)
+	v.evict(e, ghRepo, version, fileName)
+	contents, err = v.fetch(ctx, ghRepo, version, fileName)
+	if errors.Is(err, ErrVerification) {
+		v.evict(e, ghRepo, version, fileName)
+	}
+	return contents, err
+}
+
+// evict removes fileName and the checksums of its release from the source and from the checksums verified
+func (v *verifyingSource) evict(e evictingSource, ghRepo, version, fileName string) {
+	v.mu.Lock()
+	delete(v.checksums, ghRepo+"/"+version)
+	v.mu.Unlock()
+	for _, f := range []string{fileName, ChecksumFile, SignatureFile} {
+		e.Evict(ghRepo, version, f)
+	}
+}
+
+// fetch obtains fileName and verifies it against the checksums of its release
+func (v *verifyingSource) fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	contents, err := v.source.Fetch(ctx, ghRepo, version, fileName)
+	if err != nil {
+		return "", err
+	}
+	checksums, err := v.releaseChecksums(ctx, ghRepo, version)
+	if err != nil {
+		return "", err
+	}
+	expected, ok := checksums[fileName]
+	if !ok {
+		return "", fmt.Errorf("%w: %s has no checksum in %s", ErrVerification, fileName, v.source.Location(ghRepo, version, ChecksumFile))
+	}
+	sum := sha256.Sum256([]byte(contents))
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return "", fmt.Errorf("%w: checksum of %s is %s, expected %s", ErrVerification, v.source.Location(ghRepo, version, fileName), actual, expected)
+	}
+	return contents, nil
+}
+
+// Location describes where fileName is obtained from
+func (v *verifyingSource) Location(ghRepo, version, fileName string) string {
+	return v.source.Location(ghRepo, version, fileName)
+}
+
+// releaseChecksums obtains and verifies the ChecksumFile of the release once
+func (v *verifyingSource) releaseChecksums(ctx context.Context, ghRepo, version string) (map[string]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	key := ghRepo + "/" + version
+	if checksums, ok := v.checksums[key]; ok {
+		return checksums, nil
+	}
+	sums, err := v.source.Fetch(ctx, ghRepo, version, ChecksumFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: couldn't obtain the checksums of the release: %s. Releases without %s can only be used with --insecure-skip-verify",
+			ErrVerification, err, ChecksumFile)
+	}
+	if v.publicKey != nil {
+		signature, err := v.source.Fetch(ctx, ghRepo, version, SignatureFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: couldn't obtain the signature of the checksums: %s", ErrVerification, err)
+		}
+		if err := verifySignature(v.publicKey, []byte(sums), decodeSignature(signature)); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrVerification, v.source.Location(ghRepo, version, SignatureFile), err)
+		}
+	}
+	checksums, err := parseChecksums(sums)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrVerification, v.source.Location(ghRepo, version, ChecksumFile), err)
+	}
+	v.checksums[key] = checksums
+	return checksums, nil
+}
+
+// parseChecksums parses the output of sha256sum, e.g. "<hex>  base.yaml" or "<hex> *base.yaml"
+func parseChecksums(sums string) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(sums))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid checksum line %q", line)
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return checksums, scanner.Err()
+}
+
+// decodeSignature accepts raw and base64 encoded signatures
+func decodeSignature(signature string) []byte {
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature)); err == nil {
+		return decoded
+	}
+	return []byte(signature)
+}
+
+// verifySignature verifies the signature of the sha256 digest of data.
+// RSA PKCS #1 v1.5, ECDSA ASN.1 and Ed25519 signatures are supported
+func verifySignature(publicKey crypto.PublicKey, data, signature []byte) error {
+	digest := sha256.Sum256(data)
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest[:], signature) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, data, signature) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported public key type %T", publicKey)
+}
+
+// LoadPublicKey reads a PEM encoded PKIX public key
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s doesn't contain a PEM encoded public key", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key %s: %w", path, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,180 @@
+package release
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// mapSource serves the manifests of a single release from memory
+type mapSource map[string]string
+
+func (s mapSource) Fetch(ctx context.Context, ghRepo, version, fileName string) (string, error) {
+	contents, ok := s[fileName]
+	if !ok {
+		return "", fmt.Errorf("%s %w", fileName, utils.ErrNotFound)
+	}
+	return contents, nil
+}
+
+func (s mapSource) Location(ghRepo, version, fileName string) string {
+	return fileName
+}
+
+func sha256Hex(contents string) string {
+	sum := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestVerifyingSourceChecksums tests manifests are only returned when they match the published checksums
+func TestVerifyingSourceChecksums(t *testing.T) {
+	base := "kind: ConfigMap\n"
+	td := []struct {
+		// comment about test case
+		testComment string
+		// files published with the release
+		files mapSource
+		// expected contents
+		expected string
+		// expected error
+		expectedError error
+	}{
+		{
+			testComment: "manifest matching its checksum is returned",
+			files: mapSource{
+				"base.yaml":  base,
+				ChecksumFile: fmt.Sprintf("%s  base.yaml\n%s *ds.yaml\n", sha256Hex(base), sha256Hex("ds")),
+			},
+			expected: base,
+		},
+		{
+			testComment: "modified manifest fails verification",
+			files: mapSource{
+				"base.yaml":  base + "tampered",
+				ChecksumFile: fmt.Sprintf("%s  base.yaml\n", sha256Hex(base)),
+			},
+			expectedError: ErrVerification,
+		},
+		{
+			testComment: "manifest without checksum fails verification",
+			files: mapSource{
+				"base.yaml":  base,
+				ChecksumFile: fmt.Sprintf("%s  ds.yaml\n", sha256Hex("ds")),
+			},
+			expectedError: ErrVerification,
+		},
+		{
+			testComment: "release without checksums fails verification",
+			files: mapSource{
+				"base.yaml": base,
+			},
+			expectedError: ErrVerification,
+		},
+		{
+			testComment: "malformed checksums fail verification",
+			files: mapSource{
+				"base.yaml":  base,
+				ChecksumFile: "not a checksum",
+			},
+			expectedError: ErrVerification,
+		},
+		{
+			testComment: "missing manifests are not found",
+			files: mapSource{
+				ChecksumFile: fmt.Sprintf("%s  base.yaml\n", sha256Hex(base)),
+			},
+			expectedError: utils.ErrNotFound,
+		},
+	}
+
+	for _, tc := range td {
+		source := NewVerifyingSource(tc.files, nil)
+		contents, err := source.Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "base.yaml")
+		if !errors.Is(err, tc.expectedError) {
+			t.Errorf("%s expected error: %+v, found: %+v", tc.testComment, tc.expectedError, err)
+		}
+		if contents != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, contents)
+		}
+	}
+}
+
+// TestVerifyingSourceSignature tests the checksums are verified against the signature when a public key is configured
+func TestVerifyingSourceSignature(t *testing.T) {
+	base := "kind: ConfigMap\n"
+	sums := fmt.Sprintf("%s  base.yaml\n", sha256Hex(base))
+	digest := sha256.Sum256([]byte(sums))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecSignature, err := ecdsa.SignASN1(rand.Reader, ecKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edSignature := ed25519.Sign(edKey, []byte(sums))
+	otherPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	td := []struct {
+		// comment about test case
+		testComment string
+		// key used to verify the signature
+		publicKey interface{}
+		// published signature, omitted when empty
+		signature string
+		// expected error
+		expectedError error
+	}{
+		{
+			testComment: "valid raw ECDSA signature",
+			publicKey:   &ecKey.PublicKey,
+			signature:   string(ecSignature),
+		},
+		{
+			testComment: "valid base64 Ed25519 signature",
+			publicKey:   edPublic,
+			signature:   base64.StdEncoding.EncodeToString(edSignature) + "\n",
+		},
+		{
+			testComment:   "signature by another key fails verification",
+			publicKey:     otherPublic,
+			signature:     base64.StdEncoding.EncodeToString(edSignature),
+			expectedError: ErrVerification,
+		},
+		{
+			testComment:   "missing signature fails verification",
+			publicKey:     edPublic,
+			expectedError: ErrVerification,
+		},
+	}
+
+	for _, tc := range td {
+		files := mapSource{"base.yaml": base, ChecksumFile: sums}
+		if len(tc.signature) > 0 {
+			files[SignatureFile] = tc.signature
+		}
+		source := NewVerifyingSource(files, tc.publicKey)
+		_, err := source.Fetch(context.Background(), "ForgeRock/forgeops", "2020.10.28-AlSugoDiNoci", "base.yaml")
+		if !errors.Is(err, tc.expectedError) {
+			t.Errorf("%s expected error: %+v, found: %+v", tc.testComment, tc.expectedError, err)
+		}
+	}
+}
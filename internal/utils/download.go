@@ -2,27 +2,81 @@ package utils
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 )
 
+// ErrNotFound the requested file does not exist
+var ErrNotFound error = errors.New("file not found")
+
+// ErrNotModified the requested file matches the validators of the copy already downloaded
+var ErrNotModified error = errors.New("file not modified")
+
+// Validators identify the version of a downloaded file in conditional requests
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
 // DownloadTextFile downloads a file from a given URL or return an error otherwise
-func DownloadTextFile(URL string) (string, error) {
+func DownloadTextFile(ctx context.Context, URL string) (string, error) {
+	contents, _, err := DownloadTextFileIfModified(ctx, URL, Validators{})
+	return contents, err
+}
+
+// DownloadTextFileIfModified downloads a file from a given URL unless it matches the validators provided.
+// ErrNotModified is returned when the file didn't change. The validators of the downloaded file are returned otherwise
+func DownloadTextFileIfModified(ctx context.Context, URL string, v Validators) (string, Validators, error) {
+	return download(ctx, URL, "", v)
+}
+
+// DownloadAssetIfModified downloads a release asset through the GitHub API unless it matches the validators provided.
+// The API redirects to the contents of the asset when they're requested as application/octet-stream
+func DownloadAssetIfModified(ctx context.Context, URL string, v Validators) (string, Validators, error) {
+	return download(ctx, URL, "application/octet-stream", v)
+}
+
+// download requests the URL with the given Accept header and the validators provided
+func download(ctx context.Context, URL, accept string, v Validators) (string, Validators, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return "", Validators{}, err
+	}
+	if len(accept) > 0 {
+		request.Header.Set("Accept", accept)
+	}
+	if len(v.ETag) > 0 {
+		request.Header.Set("If-None-Match", v.ETag)
+	}
+	if len(v.LastModified) > 0 {
+		request.Header.Set("If-Modified-Since", v.LastModified)
+	}
 	//Get the response bytes from the url
-	response, err := http.Get(URL)
+	response, err := doWithRetries(ctx, httpClient, request)
 	if err != nil {
-		return "", err
+		return "", Validators{}, err
 	}
 	defer response.Body.Close()
 
+	if response.StatusCode == http.StatusNotModified {
+		return "", v, ErrNotModified
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return "", Validators{}, fmt.Errorf("%w: %s", ErrNotFound, URL)
+	}
 	if response.StatusCode != 200 {
-		return "", fmt.Errorf("Received non 200 response code: %d", response.StatusCode)
+		return "", Validators{}, fmt.Errorf("Received non 200 response code: %d from %s", response.StatusCode, URL)
 	}
 	contents := new(bytes.Buffer)
 	_, err = io.Copy(contents, response.Body)
 	if err != nil {
-		return "", err
+		return "", Validators{}, fmt.Errorf("error downloading %s: %w", URL, err)
 	}
-	return contents.String(), nil
+	return contents.String(), Validators{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}, nil
 }
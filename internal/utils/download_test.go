@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// configureTestHTTP configures the client without backoff delays and restores the defaults once the test is done
+func configureTestHTTP(t *testing.T, opts HTTPOptions) {
+	retryBaseDelay = time.Millisecond
+	if err := ConfigureHTTP(opts); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		retryBaseDelay = time.Second
+		ConfigureHTTP(DefaultHTTPOptions)
+	})
+}
+
+// TestDownloadRetries tests the failures retried
+func TestDownloadRetries(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		// status codes returned by the server, the last one is repeated
+		statusCodes []int
+		// retries configured
+		retries int
+		// expected requests
+		expectedRequests int
+		// expected error
+		expectedError error
+	}{
+		{
+			testComment:      "5xx are retried",
+			statusCodes:      []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			retries:          4,
+			expectedRequests: 3,
+		},
+		{
+			testComment:      "429 are retried",
+			statusCodes:      []int{http.StatusTooManyRequests, http.StatusOK},
+			retries:          4,
+			expectedRequests: 2,
+		},
+		{
+			testComment:      "404 aren't retried",
+			statusCodes:      []int{http.StatusNotFound},
+			retries:          4,
+			expectedRequests: 1,
+			expectedError:    ErrNotFound,
+		},
+		{
+			testComment:      "retries are limited",
+			statusCodes:      []int{http.StatusNotFound, http.StatusInternalServerError},
+			retries:          0,
+			expectedRequests: 1,
+			expectedError:    ErrNotFound,
+		},
+	}
+
+	for _, tc := range td {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			code := tc.statusCodes[len(tc.statusCodes)-1]
+			if requests < len(tc.statusCodes) {
+				code = tc.statusCodes[requests]
+			}
+			requests++
+			w.WriteHeader(code)
+			w.Write([]byte("contents"))
+		}))
+		configureTestHTTP(t, HTTPOptions{Timeout: 10 * time.Second, ConnectTimeout: time.Second, Retries: tc.retries})
+		_, err := DownloadTextFile(context.Background(), server.URL)
+		server.Close()
+		if !errors.Is(err, tc.expectedError) {
+			t.Errorf("%s expected error: %+v, found: %+v", tc.testComment, tc.expectedError, err)
+		}
+		if requests != tc.expectedRequests {
+			t.Errorf("%s expected %d requests, found: %d", tc.testComment, tc.expectedRequests, requests)
+		}
+	}
+}
+
+// TestDownloadCancelled tests the retries stop when the context is cancelled during the backoff
+func TestDownloadCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	configureTestHTTP(t, HTTPOptions{Timeout: 10 * time.Second, ConnectTimeout: time.Second, Retries: 4})
+	// The backoff would take longer than the test timeout
+	retryBaseDelay = time.Hour
+
+	if _, err := DownloadTextFile(ctx, server.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the download to be cancelled, found: %+v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected no retry once cancelled, found %d requests", requests)
+	}
+}
+
+// TestDownloadTLSAndToken tests the CA file and the bearer token are used
+func TestDownloadTLSAndToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if r.Header.Get("If-None-Match") == `"1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"1"`)
+		w.Write([]byte("contents"))
+	}))
+	defer server.Close()
+
+	// The certificate of the test server isn't trusted by default
+	configureTestHTTP(t, HTTPOptions{Timeout: 10 * time.Second, ConnectTimeout: time.Second})
+	if _, err := DownloadTextFile(context.Background(), server.URL); err == nil {
+		t.Errorf("expected untrusted certificate error")
+	}
+
+	caFile, err := ioutil.TempFile("", "ca-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caFile.Close()
+
+	configureTestHTTP(t, HTTPOptions{Timeout: 10 * time.Second, ConnectTimeout: time.Second, CAFile: caFile.Name(), Token: "secret"})
+	contents, v, err := DownloadTextFileIfModified(context.Background(), server.URL, Validators{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contents != "contents" || v.ETag != `"1"` {
+		t.Errorf("unexpected download: %q %+v", contents, v)
+	}
+	if authorization != "Bearer secret" {
+		t.Errorf("expected bearer token, found: %q", authorization)
+	}
+	if _, _, err := DownloadTextFileIfModified(context.Background(), server.URL, v); !errors.Is(err, ErrNotModified) {
+		t.Errorf("expected error: %+v, found: %+v", ErrNotModified, err)
+	}
+}
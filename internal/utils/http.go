@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPOptions configure the client used to download files
+type HTTPOptions struct {
+	// Timeout of each download attempt, including reading the body
+	Timeout time.Duration
+	// ConnectTimeout of the TCP connection and TLS handshake
+	ConnectTimeout time.Duration
+	// Retries of downloads failing with connection errors, 5xx or 429 responses
+	Retries int
+	// CAFile PEM encoded certificates trusted in addition to the system certificates
+	CAFile string
+	// Token sent as a bearer token, e.g. to download private release assets
+	Token string
+}
+
+// DefaultHTTPOptions used until ConfigureHTTP is called
+var DefaultHTTPOptions = HTTPOptions{
+	Timeout:        2 * time.Minute,
+	ConnectTimeout: 10 * time.Second,
+	Retries:        4,
+}
+
+// maxRetryDelay caps the exponential backoff and the Retry-After delays requested by servers
+const maxRetryDelay = 30 * time.Second
+
+// retryBaseDelay delay before the first retry, doubled on every retry
+var retryBaseDelay = time.Second
+
+var httpOptions = DefaultHTTPOptions
+var httpClient = newHTTPClient(DefaultHTTPOptions, nil)
+
+// ConfigureHTTP replaces the client used to download files.
+// Proxies are read from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables
+func ConfigureHTTP(opts HTTPOptions) error {
+	var rootCAs *x509.CertPool
+	if len(opts.CAFile) > 0 {
+		pem, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return err
+		}
+		if rootCAs, err = x509.SystemCertPool(); err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%s doesn't contain any PEM encoded certificate", opts.CAFile)
+		}
+	}
+	httpOptions = opts
+	httpClient = newHTTPClient(opts, rootCAs)
+	return nil
+}
+
+func newHTTPClient(opts HTTPOptions, rootCAs *x509.CertPool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.DialContext = (&net.Dialer{
+		Timeout:   opts.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = opts.ConnectTimeout
+	if rootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	}
+	return &http.Client{Transport: transport, Timeout: opts.Timeout}
+}
+
+// ResolveRedirect returns the location the given URL redirects to without following it
+func ResolveRedirect(ctx context.Context, URL string) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, URL, nil)
+	if err != nil {
+		return "", err
+	}
+	client := *httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	response, err := doWithRetries(ctx, &client, request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, URL)
+	}
+	location, err := response.Location()
+	if err != nil {
+		return "", fmt.Errorf("%s didn't redirect. Received response code: %d", URL, response.StatusCode)
+	}
+	return location.String(), nil
+}
+
+// doWithRetries sends the request, retrying connection errors, 5xx and 429 responses with exponential backoff.
+// The retries stop when ctx is done
+func doWithRetries(ctx context.Context, client *http.Client, request *http.Request) (*http.Response, error) {
+	if len(httpOptions.Token) > 0 {
+		request.Header.Set("Authorization", "Bearer "+httpOptions.Token)
+	}
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		response, err := client.Do(request)
+		retry := err != nil || response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests
+		if !retry || attempt >= httpOptions.Retries {
+			return response, err
+		}
+		wait := delay
+		if err == nil {
+			if after, parseErr := strconv.Atoi(response.Header.Get("Retry-After")); parseErr == nil {
+				wait = time.Duration(after) * time.Second
+			}
+			response.Body.Close()
+		}
+		if wait > maxRetryDelay {
+			wait = maxRetryDelay
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
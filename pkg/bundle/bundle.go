@@ -0,0 +1,105 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+)
+
+// Release identifies the manifests to be pulled from a GitHub release
+type Release struct {
+	GHRepo  string
+	Version string
+	Files   []string
+}
+
+// Resolve returns the releases with "latest" resolved to the tag of the latest release.
+// Bundles keep concrete tags so installs from the bundle know the release installed
+func Resolve(ctx context.Context, releases ...Release) ([]Release, error) {
+	resolved := make([]Release, 0, len(releases))
+	for _, r := range releases {
+		if len(r.Version) == 0 || r.Version == "latest" {
+			version, err := release.GitHub{}.ResolveLatest(ctx, r.GHRepo)
+			if err != nil {
+				return nil, fmt.Errorf("error resolving the latest release of %q: %w", r.GHRepo, err)
+			}
+			printer.NoticeHif("Resolved %q version: \"latest\" to %q", r.GHRepo, version)
+			r.Version = version
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved, nil
+}
+
+// Pull downloads the manifests of the given releases from GitHub and writes them in a .tar.gz bundle.
+// The bundle can be used with --bundle to install without internet access
+func Pull(ctx context.Context, bundleFile string, releases ...Release) error {
+	releases, err := Resolve(ctx, releases...)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(bundleFile)
+	if err != nil {
+		return err
+	}
+	if err := write(ctx, f, releases); err != nil {
+		f.Close()
+		os.Remove(bundleFile)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	printer.Noticef("Bundle written to %q", bundleFile)
+	return nil
+}
+
+func write(ctx context.Context, f *os.File, releases []Release) error {
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	gh := release.GitHub{}
+	total := 0
+	for _, r := range releases {
+		printer.NoticeHif("Pulling %q version: %q", r.GHRepo, r.Version)
+		for _, fileName := range r.Files {
+			contents, err := gh.Fetch(ctx, r.GHRepo, r.Version, fileName)
+			// Not every release publishes every manifest
+			if errors.Is(err, utils.ErrNotFound) {
+				printer.Warnf("%q is not published in %q version: %q. Skipping", fileName, r.GHRepo, r.Version)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			hdr := &tar.Header{
+				Name:    release.BundlePath(r.GHRepo, r.Version, fileName),
+				Mode:    0644,
+				Size:    int64(len(contents)),
+				ModTime: time.Now(),
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write([]byte(contents)); err != nil {
+				return err
+			}
+			printer.Noticef("Added %s", hdr.Name)
+			total++
+		}
+	}
+	if total == 0 {
+		return fmt.Errorf("no manifests found for the requested releases")
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
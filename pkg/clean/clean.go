@@ -1,6 +1,7 @@
 package clean
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
@@ -13,7 +14,7 @@ import (
 var errDidNotAccept = errors.New("Did not accept prompt to delete")
 
 // Clean deletes remaining forgeops resources from a given namespace
-func Clean(clientFactory factory.Factory, skipUserQ bool) error {
+func Clean(ctx context.Context, clientFactory factory.Factory, skipUserQ bool) error {
 	errs := []error{}
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	ns, err := k8sCntMgr.Namespace()
@@ -25,11 +26,11 @@ func Clean(clientFactory factory.Factory, skipUserQ bool) error {
 	printer.Warnf("Please back up your DS instance before proceeding.")
 
 	// Delete the PVCs
-	infos, err := k8sCntMgr.GetObjectsFromServer("pvc", "")
+	infos, err := k8sCntMgr.GetObjectsFromServer(ctx, "pvc", "")
 	if err != nil {
 		return err
 	}
-	if err := delete.Resources(clientFactory, infos, skipUserQ); err != nil {
+	if err := delete.Resources(ctx, clientFactory, infos, skipUserQ); err != nil {
 		errs = append(errs, err)
 	}
 	// If any errors occurred during Delete, then return error (or
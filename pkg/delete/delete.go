@@ -2,6 +2,7 @@ package delete
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"os"
 	"strings"
@@ -16,29 +17,31 @@ import (
 var errDidNotAccept = errors.New("Did not accept prompt to delete")
 
 // Manifest obtains the manifest from the given path or URL and deletes the resources listed
-func Manifest(clientFactory factory.Factory, path string, skipUserQ bool) error {
+func Manifest(ctx context.Context, clientFactory factory.Factory, path string, pathOpts k8s.PathOptions, skipUserQ bool) error {
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
-	infos, err := k8sCntMgr.GetObjectsFromPath(path)
+	infos, err := k8sCntMgr.GetObjectsFromPath(path, pathOpts)
 	if err != nil {
 		return err
 	}
-	return Resources(clientFactory, infos, skipUserQ)
+	return Resources(ctx, clientFactory, infos, skipUserQ)
 }
 
 // ManifestStr Deletes the resources listed in the given manifest provided
-func ManifestStr(clientFactory factory.Factory, manifestContents string, skipUserQ bool) error {
+func ManifestStr(ctx context.Context, clientFactory factory.Factory, manifestContents string, skipUserQ bool) error {
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	infos, err := k8sCntMgr.GetObjectsFromStream(strings.NewReader(manifestContents))
 	if err != nil {
 		return err
 	}
-	return Resources(clientFactory, infos, skipUserQ)
+	return Resources(ctx, clientFactory, infos, skipUserQ)
 }
 
 // Resources delete the resources provided
-func Resources(clientFactory factory.Factory, infos []*resource.Info, skipUserQ bool) error {
+func Resources(ctx context.Context, clientFactory factory.Factory, infos []*resource.Info, skipUserQ bool) error {
 	errs := []error{}
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
+	// Custom resources of CRDs that aren't installed can't exist
+	infos = mapped(infos)
 	if len(infos) == 0 {
 		// Ignore "notFound" errors when deleting
 		return nil
@@ -52,7 +55,7 @@ func Resources(clientFactory factory.Factory, infos []*resource.Info, skipUserQ
 	}
 	// Iterate through all objects, deleting each one.
 	for _, info := range infos {
-		if err := k8sCntMgr.DeleteObject(info); err != nil {
+		if err := k8sCntMgr.DeleteObject(ctx, info); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -67,6 +70,16 @@ func Resources(clientFactory factory.Factory, infos []*resource.Info, skipUserQ
 	return nil
 }
 
+func mapped(infos []*resource.Info) []*resource.Info {
+	result := []*resource.Info{}
+	for _, info := range infos {
+		if info.Mapping != nil {
+			result = append(result, info)
+		}
+	}
+	return result
+}
+
 func askForConfirmation(skipUserQ bool, infos []*resource.Info) (bool, error) {
 
 	if skipUserQ {
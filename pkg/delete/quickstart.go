@@ -1,58 +1,49 @@
 package delete
 
 import (
-	"fmt"
+	"context"
 	"strings"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/k8s"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
-	"github.com/ForgeRock/forgeops-cli/internal/utils"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/resource"
 )
 
-type qsSecret struct {
-	secretName string
-	keyName    []string
-	printName  []string
-}
-
-type qsConfig struct {
-	placeholderFQDN      string
-	placeholderNamespace string
-	importantSecrets     []qsSecret
-}
-
 // ForgeRockComponent Deletes the given component from the namespace provided
-func ForgeRockComponent(clientFactory factory.Factory, ghRepo, fileName, version string, skipUserQ bool) error {
+func ForgeRockComponent(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version string, skipUserQ bool) error {
 	var errs []error
-	fPath := fmt.Sprintf("https://github.com/%s/releases/latest/download/%s", ghRepo, fileName)
-	if len(version) == 0 {
-		version = "latest"
-	}
-	if version != "latest" {
-		fPath = fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", ghRepo, version, fileName)
-	}
-	config := qsConfig{
-		placeholderNamespace: "default",
-	}
+	placeholders := profile.Current().Spec.Placeholders
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	ns, err := k8sCntMgr.Namespace()
 	if err != nil {
 		return err
 	}
 	printer.NoticeHif("Targeting namespace: %q", ns)
-	manifestStr, err := utils.DownloadTextFile(fPath)
+	manifestStr, err := release.Fetch(ctx, ghRepo, version, fileName)
 	if err != nil {
 		return err
 	}
-	manifestStr = strings.ReplaceAll(manifestStr, "namespace: "+config.placeholderNamespace, "namespace: "+ns)
+	infos, err := k8sCntMgr.GetObjectsFromStream(strings.NewReader(manifestStr))
+	if err != nil {
+		return err
+	}
+	// The objects are deleted from the namespace they were installed in
+	if err := setNamespaces(infos, placeholders.Namespace, ns); err != nil {
+		return err
+	}
 	// Delete the quickstart resources listed in the manifest
-	if err := ManifestStr(clientFactory, manifestStr, skipUserQ); err != nil {
+	if err := Resources(ctx, clientFactory, infos, skipUserQ); err != nil {
 		if err == errDidNotAccept {
 			return nil
 		}
 		errs = append(errs, err)
+	} else if err := forgetComponent(ctx, clientFactory, fileName); err != nil {
+		errs = append(errs, err)
 	}
 	if len(errs) == 1 {
 		return errs[0]
@@ -64,11 +55,34 @@ func ForgeRockComponent(clientFactory factory.Factory, ghRepo, fileName, version
 
 }
 
+// setNamespaces sets the namespace of the objects the same way the install does
+func setNamespaces(infos []*resource.Info, placeholder, ns string) error {
+	crdScopes := k8s.CRDScopes(infos)
+	for _, info := range infos {
+		if _, err := k8s.SetNamespace(info, placeholder, ns, crdScopes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forgetComponent removes the inventory records of the deleted manifest.
+// The quickstart manifest holds every component
+func forgetComponent(ctx context.Context, clientFactory factory.Factory, fileName string) error {
+	if fileName == profile.Current().Spec.QuickstartManifest {
+		if err := inventory.DeleteCheckpoint(ctx, clientFactory); err != nil {
+			return err
+		}
+		return inventory.DeleteAll(ctx, clientFactory)
+	}
+	return inventory.Delete(ctx, clientFactory, inventory.ComponentName(fileName))
+}
+
 // Quickstart Installs the quickstart in the namespace provided
-func Quickstart(clientFactory factory.Factory, ghRepo, version string, skipUserQ bool) error {
+func Quickstart(ctx context.Context, clientFactory factory.Factory, ghRepo, version string, skipUserQ bool) error {
 	var errs []error
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
-	if err := ForgeRockComponent(clientFactory, ghRepo, "quickstart.yaml", version, skipUserQ); err != nil {
+	if err := ForgeRockComponent(ctx, clientFactory, ghRepo, profile.Current().Spec.QuickstartManifest, version, skipUserQ); err != nil {
 		if err == errDidNotAccept {
 			return nil
 		}
@@ -76,11 +90,11 @@ func Quickstart(clientFactory factory.Factory, ghRepo, version string, skipUserQ
 	}
 
 	// Delete the PVCs
-	infos, err := k8sCntMgr.GetObjectsFromServer("pvc", "")
+	infos, err := k8sCntMgr.GetObjectsFromServer(ctx, "pvc", "")
 	if err != nil {
 		return err
 	}
-	if err := Resources(clientFactory, infos, true); err != nil {
+	if err := Resources(ctx, clientFactory, infos, true); err != nil {
 		if err == errDidNotAccept {
 			return nil
 		}
@@ -0,0 +1,79 @@
+package delete
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// newTestInfo builds the info of the object in the given YAML. Objects without scope are unmapped,
+// like the custom resources of CRDs not established yet
+func newTestInfo(t *testing.T, manifest string, scope meta.RESTScope) *resource.Info {
+	data, err := yaml.YAMLToJSON([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	info := &resource.Info{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Object:    obj,
+	}
+	if scope != nil {
+		gvk := obj.GroupVersionKind()
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		info.Mapping = &meta.RESTMapping{Resource: gvr, GroupVersionKind: gvk, Scope: scope}
+	}
+	return info
+}
+
+// TestSetNamespaces tests the objects are deleted from the namespace they were installed in, quoted namespaces included
+func TestSetNamespaces(t *testing.T) {
+	quoted := newTestInfo(t, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: quoted
+  namespace: "default"
+`, meta.RESTScopeNamespace)
+	missing := newTestInfo(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: missing
+`, meta.RESTScopeNamespace)
+	explicit := newTestInfo(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: explicit
+  namespace: kube-system
+`, meta.RESTScopeNamespace)
+	crd := newTestInfo(t, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: directoryservices.directory.forgerock.io
+`, meta.RESTScopeRoot)
+
+	if err := setNamespaces([]*resource.Info{quoted, missing, explicit, crd}, "default", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range []*resource.Info{quoted, missing} {
+		if info.Namespace != "prod" {
+			t.Errorf("expected %q to be deleted from prod, found %q", info.Name, info.Namespace)
+		}
+	}
+	if explicit.Namespace != "kube-system" {
+		t.Errorf("expected explicit namespaces to be left untouched, found %q", explicit.Namespace)
+	}
+	if crd.Namespace != "" {
+		t.Errorf("expected cluster scoped objects to have no namespace, found %q", crd.Namespace)
+	}
+}
@@ -1,30 +1,29 @@
 package delete
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
 )
 
 // GHResource Uninstalls resources listed in manifests publised on github
-func GHResource(clientFactory factory.Factory, ghRepo, fileName, version string, sharedWarn, skipUserQ bool) error {
-	fPath := fmt.Sprintf("https://github.com/%s/releases/latest/download/%s", ghRepo, fileName)
-	if len(version) == 0 {
-		version = "latest"
-	}
-	if version != "latest" {
-		fPath = fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", ghRepo, version, fileName)
-	}
+func GHResource(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version string, sharedWarn, skipUserQ bool) error {
 	if sharedWarn {
 		printer.Warnf("Danger zone: You're about to delete a shared operator which may be required by other deployments in this cluster.")
 		printer.Warnf("You normally do not want to delete this if you share this Kubernetes cluster with other users.")
 	}
-	if err := Manifest(clientFactory, fPath, skipUserQ); err != nil {
+	manifestStr, err := release.Fetch(ctx, ghRepo, version, fileName)
+	if err != nil {
+		return err
+	}
+	if err := ManifestStr(ctx, clientFactory, manifestStr, skipUserQ); err != nil {
 		if err == errDidNotAccept {
 			return nil
 		}
 		return err
 	}
-	return nil
+	return inventory.Delete(ctx, clientFactory, inventory.ComponentName(fileName))
 }
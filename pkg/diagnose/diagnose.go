@@ -0,0 +1,425 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Options controls what's collected in a diagnosis
+type Options struct {
+	// LogLines number of log lines collected from each failing container
+	LogLines int64
+	// Events number of most recent events kept
+	Events int
+	// File the diagnosis is also written to when set
+	File string
+}
+
+// DefaultOptions options used when none are given
+var DefaultOptions = Options{LogLines: 20, Events: 10}
+
+// Condition condition reported in the status of an object
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Container state of a container of a pod
+type Container struct {
+	Name         string `json:"name"`
+	Init         bool   `json:"init,omitempty"`
+	Ready        bool   `json:"ready"`
+	RestartCount int32  `json:"restartCount"`
+	State        string `json:"state"`
+	LastState    string `json:"lastState,omitempty"`
+	// Logs last lines logged by failing containers
+	Logs string `json:"logs,omitempty"`
+}
+
+// Pod state of a pod of the object
+type Pod struct {
+	Name       string      `json:"name"`
+	Phase      string      `json:"phase"`
+	Containers []Container `json:"containers"`
+}
+
+// Event event recorded for the object, its pods or the ReplicaSets and jobs creating them
+type Event struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Object  string    `json:"object"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Count   int32     `json:"count,omitempty"`
+}
+
+// Diagnosis state of an object that didn't become ready, of its pods and their recent events
+type Diagnosis struct {
+	Resource   string      `json:"resource"`
+	Namespace  string      `json:"namespace"`
+	Name       string      `json:"name"`
+	Found      bool        `json:"found"`
+	Status     []string    `json:"status,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	Pods       []Pod       `json:"pods,omitempty"`
+	Events     []Event     `json:"events,omitempty"`
+}
+
+// Object collects the diagnosis of the given object
+func Object(ctx context.Context, clientFactory factory.Factory, gvr schema.GroupVersionResource, ns, name string, opts Options) (*Diagnosis, error) {
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return nil, err
+	}
+	dclient, err := clientFactory.DynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	return collect(ctx, sclient, dclient, gvr, ns, name, opts)
+}
+
+func collect(ctx context.Context, sclient kubernetes.Interface, dclient dynamic.Interface, gvr schema.GroupVersionResource, ns, name string, opts Options) (*Diagnosis, error) {
+	d := &Diagnosis{Resource: gvr.Resource, Namespace: ns, Name: name}
+	obj, err := dclient.Resource(gvr).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	involved := []string{name}
+	if err == nil {
+		d.Found = true
+		d.Status = statusSummary(obj)
+		d.Conditions = conditions(obj)
+		pods, err := podsOf(ctx, sclient, gvr, obj)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pods {
+			pod := podState(&pods[i])
+			for j, c := range pod.Containers {
+				if failing(c) {
+					pod.Containers[j].Logs = containerLogs(ctx, sclient, &pods[i], c, opts.LogLines)
+				}
+			}
+			d.Pods = append(d.Pods, pod)
+			involved = append(involved, pod.Name)
+		}
+		// Pods that can't be created are only reported in the events of their controller, e.g. FailedCreate
+		owned, err := ownedBy(ctx, sclient, gvr, obj)
+		if err != nil {
+			return nil, err
+		}
+		involved = append(involved, owned...)
+	}
+	if d.Events, err = events(ctx, sclient, ns, involved, opts.Events); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// statusSummary lists the scalar fields of the status of the object, e.g. readyReplicas=0
+func statusSummary(obj *unstructured.Unstructured) []string {
+	status, _, _ := unstructured.NestedMap(obj.Object, "status")
+	summary := []string{}
+	for k, v := range status {
+		switch v.(type) {
+		case int64, float64, bool, string:
+			summary = append(summary, fmt.Sprintf("%s=%v", k, v))
+		}
+	}
+	sort.Strings(summary)
+	return summary
+}
+
+func conditions(obj *unstructured.Unstructured) []Condition {
+	items, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	result := []Condition{}
+	for _, item := range items {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := Condition{}
+		condition.Type, _, _ = unstructured.NestedString(c, "type")
+		condition.Status, _, _ = unstructured.NestedString(c, "status")
+		condition.Reason, _, _ = unstructured.NestedString(c, "reason")
+		condition.Message, _, _ = unstructured.NestedString(c, "message")
+		result = append(result, condition)
+	}
+	return result
+}
+
+// Pods returns the pods of the given object, e.g. the pods of a deployment or a job
+func Pods(ctx context.Context, clientFactory factory.Factory, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) ([]corev1.Pod, error) {
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return nil, err
+	}
+	return podsOf(ctx, sclient, gvr, obj)
+}
+
+// podsOf returns the pods selected by the object, e.g. the pods of a deployment or a job
+func podsOf(ctx context.Context, sclient kubernetes.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) ([]corev1.Pod, error) {
+	pods := sclient.CoreV1().Pods(obj.GetNamespace())
+	if gvr.Group == "" && gvr.Resource == "pods" {
+		pod, err := pods.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	}
+	raw, found, _ := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if !found {
+		return nil, nil
+	}
+	labelSelector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, labelSelector); err != nil {
+		return nil, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil || selector.Empty() {
+		return nil, nil
+	}
+	list, err := pods.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ownedBy returns the names of the objects the object creates its pods through: the ReplicaSets of a deployment
+// or the jobs of a cronjob
+func ownedBy(ctx context.Context, sclient kubernetes.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) ([]string, error) {
+	owned := []metav1.ObjectMeta{}
+	switch {
+	case gvr.Group == "apps" && gvr.Resource == "deployments":
+		list, err := sclient.AppsV1().ReplicaSets(obj.GetNamespace()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, rs := range list.Items {
+			owned = append(owned, rs.ObjectMeta)
+		}
+	case gvr.Group == "batch" && gvr.Resource == "cronjobs":
+		list, err := sclient.BatchV1().Jobs(obj.GetNamespace()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range list.Items {
+			owned = append(owned, job.ObjectMeta)
+		}
+	}
+	names := []string{}
+	for _, o := range owned {
+		for _, ref := range o.OwnerReferences {
+			if ref.UID == obj.GetUID() {
+				names = append(names, o.Name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+func podState(pod *corev1.Pod) Pod {
+	p := Pod{Name: pod.Name, Phase: string(pod.Status.Phase), Containers: []Container{}}
+	for _, s := range pod.Status.InitContainerStatuses {
+		c := containerState(s)
+		c.Init = true
+		p.Containers = append(p.Containers, c)
+	}
+	for _, s := range pod.Status.ContainerStatuses {
+		p.Containers = append(p.Containers, containerState(s))
+	}
+	return p
+}
+
+func containerState(s corev1.ContainerStatus) Container {
+	return Container{
+		Name:         s.Name,
+		Ready:        s.Ready,
+		RestartCount: s.RestartCount,
+		State:        describeState(s.State),
+		LastState:    describeState(s.LastTerminationState),
+	}
+}
+
+// describeState returns a one line description of the state of a container, e.g. waiting: CrashLoopBackOff
+func describeState(state corev1.ContainerState) string {
+	switch {
+	case state.Waiting != nil:
+		return joinNonEmpty("waiting", state.Waiting.Reason, state.Waiting.Message)
+	case state.Terminated != nil:
+		return joinNonEmpty("terminated", state.Terminated.Reason,
+			fmt.Sprintf("exit code %d", state.Terminated.ExitCode), state.Terminated.Message)
+	case state.Running != nil:
+		return "running"
+	}
+	return ""
+}
+
+func joinNonEmpty(state string, details ...string) string {
+	nonEmpty := []string{}
+	for _, d := range details {
+		if len(d) > 0 {
+			nonEmpty = append(nonEmpty, d)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return state
+	}
+	return state + ": " + strings.Join(nonEmpty, ", ")
+}
+
+// failing is true for containers that aren't ready or restarted. Init containers are failing until they complete
+func failing(c Container) bool {
+	if c.Init {
+		return c.RestartCount > 0 || !strings.HasPrefix(c.State, "terminated: Completed")
+	}
+	return !c.Ready || c.RestartCount > 0
+}
+
+// containerLogs returns the last lines logged by the container. The logs of the previous instance are returned
+// when the container is waiting to restart
+func containerLogs(ctx context.Context, sclient kubernetes.Interface, pod *corev1.Pod, c Container, lines int64) string {
+	if lines <= 0 {
+		return ""
+	}
+	logOpts := &corev1.PodLogOptions{
+		Container: c.Name,
+		TailLines: &lines,
+		Previous:  strings.HasPrefix(c.State, "waiting") && strings.HasPrefix(c.LastState, "terminated"),
+	}
+	logs, err := sclient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOpts).Do(ctx).Raw()
+	if err != nil {
+		return fmt.Sprintf("(logs unavailable: %s)", err)
+	}
+	return strings.TrimRight(string(logs), "\n")
+}
+
+// events returns the most recent events of the given objects, oldest first
+func events(ctx context.Context, sclient kubernetes.Interface, ns string, names []string, limit int) ([]Event, error) {
+	result := []Event{}
+	for _, name := range names {
+		selector := fields.OneTermEqualSelector("involvedObject.name", name).String()
+		list, err := sclient.CoreV1().Events(ns).List(ctx, metav1.ListOptions{FieldSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range list.Items {
+			// Fake clients ignore field selectors
+			if e.InvolvedObject.Name != name {
+				continue
+			}
+			result = append(result, Event{
+				Time:    eventTime(e),
+				Type:    e.Type,
+				Object:  strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name,
+				Reason:  e.Reason,
+				Message: e.Message,
+				Count:   e.Count,
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result, nil
+}
+
+func eventTime(e corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// String returns the diagnosis as a concise report
+func (d *Diagnosis) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Diagnosis of %s %q in namespace %q\n", d.Resource, d.Name, d.Namespace)
+	if !d.Found {
+		fmt.Fprintf(&b, "  %s %q doesn't exist\n", d.Resource, d.Name)
+	}
+	if len(d.Status) > 0 {
+		fmt.Fprintf(&b, "Status: %s\n", strings.Join(d.Status, " "))
+	}
+	if len(d.Conditions) > 0 {
+		fmt.Fprintln(&b, "Conditions:")
+		for _, c := range d.Conditions {
+			fmt.Fprintf(&b, "  %s=%s", c.Type, c.Status)
+			if len(c.Reason) > 0 {
+				fmt.Fprintf(&b, " (%s)", c.Reason)
+			}
+			if len(c.Message) > 0 {
+				fmt.Fprintf(&b, ": %s", c.Message)
+			}
+			fmt.Fprintln(&b)
+		}
+	}
+	if d.Found && len(d.Pods) == 0 {
+		fmt.Fprintln(&b, "Pods: none")
+	}
+	if len(d.Pods) > 0 {
+		fmt.Fprintln(&b, "Pods:")
+	}
+	for _, p := range d.Pods {
+		fmt.Fprintf(&b, "  %s %s\n", p.Name, p.Phase)
+		for _, c := range p.Containers {
+			kind := "container"
+			if c.Init {
+				kind = "init container"
+			}
+			fmt.Fprintf(&b, "    %s %s: ready=%t restarts=%d %s\n", kind, c.Name, c.Ready, c.RestartCount, c.State)
+			if len(c.LastState) > 0 {
+				fmt.Fprintf(&b, "      last state: %s\n", c.LastState)
+			}
+			if len(c.Logs) > 0 {
+				fmt.Fprintln(&b, "      logs:")
+				for _, line := range strings.Split(c.Logs, "\n") {
+					fmt.Fprintf(&b, "        %s\n", line)
+				}
+			}
+		}
+	}
+	if len(d.Events) > 0 {
+		fmt.Fprintln(&b, "Events:")
+		for _, e := range d.Events {
+			fmt.Fprintf(&b, "  %s %s %s %s: %s", e.Time.UTC().Format(time.RFC3339), e.Type, e.Object, e.Reason, e.Message)
+			if e.Count > 1 {
+				fmt.Fprintf(&b, " (x%d)", e.Count)
+			}
+			fmt.Fprintln(&b)
+		}
+	}
+	return b.String()
+}
+
+// WriteFile writes the report to the given file
+func (d *Diagnosis) WriteFile(path string) error {
+	return ioutil.WriteFile(path, []byte(d.String()), 0644)
+}
@@ -0,0 +1,199 @@
+package diagnose
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestStatefulSet() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "StatefulSet",
+		"metadata":   map[string]interface{}{"name": "ds-idrepo", "namespace": "test"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "ds-idrepo"}},
+		},
+		"status": map[string]interface{}{
+			"replicas":      int64(1),
+			"readyReplicas": int64(0),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "PodNotReady"},
+			},
+		},
+	}}
+}
+
+func newTestPod(name string, labels map[string]string, statuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: labels},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: statuses},
+	}
+}
+
+func newTestEvent(name, object, reason string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "test"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: object},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+// TestCollect tests the object, its pods, their failing containers and their events are diagnosed
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	crashing := corev1.ContainerStatus{
+		Name:                 "ds",
+		RestartCount:         3,
+		State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+	}
+	running := corev1.ContainerStatus{
+		Name:  "sidecar",
+		Ready: true,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}
+	sclient := fake.NewSimpleClientset(
+		newTestPod("ds-idrepo-0", map[string]string{"app": "ds-idrepo"}, crashing, running),
+		newTestPod("am-0", map[string]string{"app": "am"}),
+		newTestEvent("e1", "ds-idrepo-0", "BackOff", now),
+		newTestEvent("e2", "ds-idrepo-0", "Pulled", now.Add(-time.Minute)),
+		newTestEvent("e3", "am-0", "Unrelated", now),
+	)
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newTestStatefulSet())
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+
+	d, err := collect(context.Background(), sclient, dclient, gvr, "test", "ds-idrepo", Options{LogLines: 5, Events: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Found {
+		t.Fatal("expected the statefulset to be found")
+	}
+	if strings.Join(d.Status, " ") != "readyReplicas=0 replicas=1" {
+		t.Errorf("unexpected status summary: %v", d.Status)
+	}
+	if len(d.Conditions) != 1 || d.Conditions[0].Reason != "PodNotReady" {
+		t.Errorf("unexpected conditions: %+v", d.Conditions)
+	}
+	if len(d.Pods) != 1 || d.Pods[0].Name != "ds-idrepo-0" {
+		t.Fatalf("expected only the pods of the statefulset, found: %+v", d.Pods)
+	}
+	containers := d.Pods[0].Containers
+	if containers[0].State != "waiting: CrashLoopBackOff" || containers[0].LastState != "terminated: Error, exit code 1" {
+		t.Errorf("unexpected container states: %+v", containers[0])
+	}
+	if containers[0].Logs != "fake logs" {
+		t.Errorf("expected the logs of the failing container, found: %q", containers[0].Logs)
+	}
+	if len(containers[1].Logs) != 0 {
+		t.Errorf("expected no logs for the ready container, found: %q", containers[1].Logs)
+	}
+	if len(d.Events) != 2 || d.Events[0].Reason != "Pulled" || d.Events[1].Reason != "BackOff" {
+		t.Errorf("expected the events of the pod, oldest first, found: %+v", d.Events)
+	}
+
+	report := d.String()
+	for _, expected := range []string{`statefulsets "ds-idrepo"`, "Ready=False (PodNotReady)", "CrashLoopBackOff", "fake logs", "BackOff"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("expected %q in the report:\n%s", expected, report)
+		}
+	}
+}
+
+// TestCollectMissing tests objects that weren't created are reported as missing
+func TestCollectMissing(t *testing.T) {
+	sclient := fake.NewSimpleClientset()
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	d, err := collect(context.Background(), sclient, dclient, gvr, "test", "am", DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Found {
+		t.Error("expected am not to be found")
+	}
+	if !strings.Contains(d.String(), `deployments "am" doesn't exist`) {
+		t.Errorf("expected the missing object in the report:\n%s", d.String())
+	}
+}
+
+// TestCollectOwned tests the events of the ReplicaSets of a deployment are collected when its pods can't be created
+func TestCollectOwned(t *testing.T) {
+	now := time.Now()
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "am", "namespace": "test", "uid": "am-uid"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "am"}},
+		},
+	}}
+	newReplicaSet := func(name string, owner types.UID) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "test",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "owner", UID: owner}},
+		}}
+	}
+	failedCreate := newTestEvent("e1", "am-5d9f7", "FailedCreate", now)
+	failedCreate.InvolvedObject.Kind = "ReplicaSet"
+	failedCreate.Message = `pods "am-5d9f7-" is forbidden: exceeded quota`
+	unrelated := newTestEvent("e2", "idm-7c8b6", "FailedCreate", now)
+	unrelated.InvolvedObject.Kind = "ReplicaSet"
+	sclient := fake.NewSimpleClientset(
+		newReplicaSet("am-5d9f7", "am-uid"),
+		newReplicaSet("idm-7c8b6", "idm-uid"),
+		failedCreate,
+		unrelated,
+	)
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), deployment)
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	d, err := collect(context.Background(), sclient, dclient, gvr, "test", "am", DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Pods) != 0 {
+		t.Errorf("expected no pods, found: %+v", d.Pods)
+	}
+	if len(d.Events) != 1 || d.Events[0].Object != "replicaset/am-5d9f7" || d.Events[0].Reason != "FailedCreate" {
+		t.Errorf("expected the events of the ReplicaSet of the deployment, found: %+v", d.Events)
+	}
+	if !strings.Contains(d.String(), "exceeded quota") {
+		t.Errorf("expected the event message in the report:\n%s", d.String())
+	}
+}
+
+// TestEventsLimit tests only the most recent events are kept
+func TestEventsLimit(t *testing.T) {
+	now := time.Now()
+	sclient := fake.NewSimpleClientset(
+		newTestEvent("e1", "am", "First", now.Add(-2*time.Minute)),
+		newTestEvent("e2", "am", "Second", now.Add(-time.Minute)),
+		newTestEvent("e3", "am", "Third", now),
+	)
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	d, err := collect(context.Background(), sclient, dclient, gvr, "test", "am", Options{Events: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Events) != 2 || d.Events[0].Reason != "Second" || d.Events[1].Reason != "Third" {
+		t.Errorf("expected the 2 most recent events, found: %+v", d.Events)
+	}
+}
@@ -0,0 +1,198 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/pkg/install"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// serverPopulatedFields are set by the API server and are never part of a manifest
+var serverPopulatedFields = [][]string{
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "uid"},
+	{"metadata", "selfLink"},
+	{"metadata", "generation"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+	{"metadata", "annotations", "deployment.kubernetes.io/revision"},
+	{"status"},
+}
+
+// ConfigureOutput leaves stdout to the unified diffs so they can be fed to patch or diff tooling.
+// The notices, e.g. the number of objects that would change, are written to stderr
+func ConfigureOutput() {
+	printer.SetConsoleOutput(os.Stderr)
+}
+
+// ForgeRockComponent prints the differences between the component manifest and the live objects.
+// It returns the number of objects that would change
+func ForgeRockComponent(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version, fqdn string) (int, error) {
+	rm, err := install.RenderForgeRockComponent(ctx, clientFactory, ghRepo, fileName, version, fqdn)
+	if err != nil {
+		return 0, err
+	}
+	return Resources(ctx, clientFactory, rm.Infos)
+}
+
+// GHResource prints the differences between the manifest published on github and the live objects.
+// It returns the number of objects that would change
+func GHResource(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version string) (int, error) {
+	rm, err := install.RenderGHResource(ctx, clientFactory, ghRepo, fileName, version)
+	if err != nil {
+		return 0, err
+	}
+	return Resources(ctx, clientFactory, rm.Infos)
+}
+
+// Quickstart prints the differences between the components of every tier and the live objects.
+// It returns the number of objects that would change
+func Quickstart(ctx context.Context, clientFactory factory.Factory, ghRepo, version, fqdn string) (int, error) {
+	p := profile.Current()
+	errs := []error{}
+	changed := 0
+	for _, tier := range p.Spec.Tiers {
+		for _, componentName := range tier.Components {
+			component, err := p.Component(componentName)
+			if err != nil {
+				return changed, err
+			}
+			n, err := ForgeRockComponent(ctx, clientFactory, ghRepo, component.Manifest, version, fqdn)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			changed += n
+		}
+	}
+	return changed, utilerrors.NewAggregate(errs)
+}
+
+// Resources prints a unified diff between each object provided and its live counterpart.
+// It returns the number of objects that would change
+func Resources(ctx context.Context, clientFactory factory.Factory, infos []*resource.Info) (int, error) {
+	errs := []error{}
+	changed := 0
+	for _, info := range infos {
+		diff, err := Object(info)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(diff) == 0 {
+			continue
+		}
+		changed++
+		printer.Document(diff)
+	}
+	printer.Noticef("%d / %d objects would change", changed, len(infos))
+	return changed, utilerrors.NewAggregate(errs)
+}
+
+// Object returns a unified diff between the object provided and the live object.
+// An empty string is returned when there are no differences
+func Object(info *resource.Info) (string, error) {
+	desired, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+	if err != nil {
+		return "", err
+	}
+	desired = runtime.DeepCopyJSON(desired)
+	// info.Get replaces the object. Use a copy to keep the desired object untouched
+	liveInfo := *info
+	live := map[string]interface{}{}
+	// Custom resources of CRDs that aren't installed yet can't exist
+	found := info.Mapping != nil
+	if found {
+		if err := liveInfo.Get(); apierrors.IsNotFound(err) {
+			found = false
+		} else if err != nil {
+			return "", err
+		}
+	}
+	if found {
+		if live, err = runtime.DefaultUnstructuredConverter.ToUnstructured(liveInfo.Object); err != nil {
+			return "", err
+		}
+		live = pruneToShape(removeServerFields(live), desired).(map[string]interface{})
+	}
+	desired = removeServerFields(desired)
+
+	kind := info.Object.GetObjectKind().GroupVersionKind().Kind
+	liveYaml, err := toYaml(live)
+	if err != nil {
+		return "", err
+	}
+	desiredYaml, err := toYaml(desired)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(liveYaml),
+		B:        difflib.SplitLines(desiredYaml),
+		FromFile: fmt.Sprintf("live/%s/%s/%s", info.Namespace, kind, info.Name),
+		ToFile:   fmt.Sprintf("manifest/%s/%s/%s", info.Namespace, kind, info.Name),
+		Context:  3,
+	})
+}
+
+func toYaml(obj map[string]interface{}) (string, error) {
+	if len(obj) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(obj)
+	return string(data), err
+}
+
+func removeServerFields(obj map[string]interface{}) map[string]interface{} {
+	for _, field := range serverPopulatedFields {
+		unstructured.RemoveNestedField(obj, field...)
+	}
+	if annotations, found, _ := unstructured.NestedMap(obj, "metadata", "annotations"); found && len(annotations) == 0 {
+		unstructured.RemoveNestedField(obj, "metadata", "annotations")
+	}
+	return obj
+}
+
+// pruneToShape drops the fields of the live object that aren't present in the desired object.
+// Those fields are defaulted by the API server or owned by other controllers and are left untouched by an apply
+func pruneToShape(live, desired interface{}) interface{} {
+	switch desiredVal := desired.(type) {
+	case map[string]interface{}:
+		liveVal, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		pruned := map[string]interface{}{}
+		for k, v := range liveVal {
+			if d, found := desiredVal[k]; found {
+				pruned[k] = pruneToShape(v, d)
+			}
+		}
+		return pruned
+	case []interface{}:
+		liveVal, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		pruned := make([]interface{}, len(liveVal))
+		for i, v := range liveVal {
+			pruned[i] = v
+			if i < len(desiredVal) {
+				pruned[i] = pruneToShape(v, desiredVal[i])
+			}
+		}
+		return pruned
+	}
+	return live
+}
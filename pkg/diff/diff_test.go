@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// TestPruneToShape tests that live fields unknown to the manifest are ignored
+func TestPruneToShape(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		// live object
+		live map[string]interface{}
+		// desired object
+		desired map[string]interface{}
+		// expected pruned live object
+		expected map[string]interface{}
+	}{
+		{
+			testComment: "defaulted fields are dropped",
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas":        int64(1),
+					"revisionHistory": int64(10),
+				},
+			},
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(2),
+				},
+			},
+			expected: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(1),
+				},
+			},
+		},
+		{
+			testComment: "list items are pruned by position and extra items are kept",
+			live: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "am", "terminationMessagePath": "/dev/termination-log"},
+					map[string]interface{}{"name": "sidecar"},
+				},
+			},
+			desired: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "am"},
+				},
+			},
+			expected: map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "am"},
+					map[string]interface{}{"name": "sidecar"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range td {
+		pruned := pruneToShape(tc.live, tc.desired)
+		if !reflect.DeepEqual(pruned, tc.expected) {
+			t.Errorf("%s expected: %+v, found: %+v", tc.testComment, tc.expected, pruned)
+		}
+	}
+}
+
+// TestRemoveServerFields tests that server populated fields are ignored
+func TestRemoveServerFields(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "am",
+			"resourceVersion": "1234",
+			"annotations": map[string]interface{}{
+				"deployment.kubernetes.io/revision": "3",
+			},
+		},
+		"status": map[string]interface{}{"replicas": int64(1)},
+	}
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": "am",
+		},
+	}
+	if found := removeServerFields(obj); !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected: %+v, found: %+v", expected, found)
+	}
+}
+
+// TestResourcesOutput tests stdout only carries the unified diffs and the objects that would change are counted
+func TestResourcesOutput(t *testing.T) {
+	dir := t.TempDir()
+	stdout, err := ioutil.TempFile(dir, "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := ioutil.TempFile(dir, "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = origStdout, origStderr
+		printer.SetConsoleOutput(os.Stdout)
+	})
+	// The notices are printed to stdout by default
+	printer.SetConsoleOutput(os.Stdout)
+	ConfigureOutput()
+
+	// Custom resources of CRDs that aren't installed yet have no mapping and don't exist
+	ds := &unstructured.Unstructured{}
+	ds.SetAPIVersion("directory.forgerock.io/v1alpha1")
+	ds.SetKind("DirectoryService")
+	ds.SetName("ds-idrepo")
+	ds.SetNamespace("prod")
+	changed, err := Resources(context.Background(), nil, []*resource.Info{{Name: "ds-idrepo", Namespace: "prod", Object: ds}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 1 {
+		t.Errorf("expected 1 object to change, found %d", changed)
+	}
+
+	out, err := ioutil.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if len(lines) < 3 || lines[0] != "--- live/prod/DirectoryService/ds-idrepo" || lines[1] != "+++ manifest/prod/DirectoryService/ds-idrepo" {
+		t.Fatalf("expected a unified diff on stdout, found: %q", out)
+	}
+	for _, line := range lines[2:] {
+		if !strings.HasPrefix(line, "@@") && !strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, " ") {
+			t.Errorf("expected only the diff on stdout, found: %q", line)
+		}
+	}
+	if notices, err := ioutil.ReadFile(stderr.Name()); err != nil || !strings.Contains(string(notices), "1 / 1 objects would change") {
+		t.Errorf("expected the summary on stderr, found: %q, %+v", notices, err)
+	}
+}
@@ -0,0 +1,53 @@
+package get
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+)
+
+// RepoReleases releases published in a GitHub repo
+type RepoReleases struct {
+	Repo     string            `json:"repo"`
+	Releases []release.Release `json:"releases"`
+}
+
+// Releases prints the most recent releases of the given repos as a table or as json, depending on the output type
+func Releases(ctx context.Context, repos []string, limit int) error {
+	results := []RepoReleases{}
+	for _, repo := range repos {
+		releases, err := release.List(ctx, repo, limit)
+		if err != nil {
+			return err
+		}
+		results = append(results, RepoReleases{Repo: repo, Releases: releases})
+	}
+	if printer.CommandOut == printer.OutJson {
+		printer.JsonDocument("forgeops get releases", results)
+		return nil
+	}
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tTAG\tPUBLISHED\tASSETS")
+	for _, r := range results {
+		for _, rel := range r.Releases {
+			tag := rel.Tag
+			if rel.Prerelease {
+				tag += " (pre-release)"
+			}
+			assets := strings.Join(rel.Assets, ",")
+			if len(assets) == 0 {
+				assets = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Repo, tag, rel.PublishedAt.UTC().Format("2006-01-02"), assets)
+		}
+	}
+	w.Flush()
+	printer.Document(buf.String())
+	return nil
+}
@@ -9,51 +9,17 @@ import (
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/k8s"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
-type qsSecret struct {
-	secretName string
-	keyName    []string
-	printName  []string
-}
-
-type qsConfig struct {
-	placeholderFQDN      string
-	placeholderNamespace string
-	importantSecrets     []qsSecret
-}
-
 // Secrets returns relevant secrets
-func Secrets(clientFactory factory.Factory) error {
-	// TODO: We should obtain settings like these from a config that can be ingested at runtime.
-	// Storing these here for now until we have a solution
-	// https://github.com/ForgeRock/forgeops-cli/issues/58
-	config := qsConfig{
-		importantSecrets: []qsSecret{
-			{
-				secretName: "am-env-secrets",
-				keyName:    []string{"AM_PASSWORDS_AMADMIN_CLEAR"},
-				printName:  []string{"amadmin user"},
-			},
-			{
-				secretName: "ds-passwords",
-				keyName:    []string{"dirmanager.pw"},
-				printName:  []string{"uid=admin user"},
-			},
-			{
-				secretName: "rcs-agent-env-secrets",
-				keyName:    []string{"AGENT_IDM_SECRET", "AGENT_RCS_SECRET"},
-				printName:  []string{"rcs-agent IDM secret", "rcs-agent RCS secret"},
-			},
-		},
-	}
-	return printSecret(clientFactory, config.importantSecrets)
+func Secrets(ctx context.Context, clientFactory factory.Factory) error {
+	return printSecret(ctx, clientFactory, profile.Current().Spec.ImportantSecrets)
 }
 
-func printSecret(clientFactory factory.Factory, importantSecrets []qsSecret) error {
-	ctx := context.Background()
+func printSecret(ctx context.Context, clientFactory factory.Factory, importantSecrets []profile.Secret) error {
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	ns, err := k8sCntMgr.Namespace()
 	if err != nil {
@@ -69,18 +35,18 @@ func printSecret(clientFactory factory.Factory, importantSecrets []qsSecret) err
 		printer.Noticef("Relevant passwords:")
 	}
 	for _, s := range importantSecrets {
-		k8sSecret, err := sclient.CoreV1().Secrets(ns).Get(ctx, s.secretName, metav1.GetOptions{})
+		k8sSecret, err := sclient.CoreV1().Secrets(ns).Get(ctx, s.Name, metav1.GetOptions{})
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
-		for idx, key := range s.keyName {
+		for _, key := range s.Keys {
 			switch printer.CommandOut {
 			case printer.OutJson:
-				secretKeyPair = append(secretKeyPair, strings.ReplaceAll(s.printName[idx], " ", "_"))
-				secretKeyPair = append(secretKeyPair, string(k8sSecret.Data[key]))
+				secretKeyPair = append(secretKeyPair, strings.ReplaceAll(key.Description, " ", "_"))
+				secretKeyPair = append(secretKeyPair, string(k8sSecret.Data[key.Key]))
 			case printer.OutText:
-				printer.NoticeHiln(fmt.Sprintf("%s (%s)", string(k8sSecret.Data[key]), s.printName[idx]))
+				printer.NoticeHiln(fmt.Sprintf("%s (%s)", string(k8sSecret.Data[key.Key]), key.Description))
 			}
 		}
 	}
@@ -95,8 +61,7 @@ func printSecret(clientFactory factory.Factory, importantSecrets []qsSecret) err
 }
 
 // URLs returns releval URLs
-func URLs(clientFactory factory.Factory, platformIngressName string) error {
-	ctx := context.Background()
+func URLs(ctx context.Context, clientFactory factory.Factory, platformIngressName string) error {
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	ns, err := k8sCntMgr.Namespace()
 	if err != nil {
@@ -1,6 +1,8 @@
 package health
 
 import (
+	"context"
+
 	"sigs.k8s.io/yaml"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
@@ -23,9 +25,9 @@ func GetHealthFromBytes(hbytes []byte) (*Health, error) {
 }
 
 // Run complete a check on a health object - for CLI based use
-func Run(clientFactory factory.Factory, hlth *Health, allNamespaces bool) (bool, error) {
+func Run(ctx context.Context, clientFactory factory.Factory, hlth *Health, allNamespaces bool) (bool, error) {
 	clientMgr := k8s.NewK8sClientMgr(clientFactory)
-	allHealthy, err := hlth.CheckResources(clientMgr, allNamespaces)
+	allHealthy, err := hlth.CheckResources(ctx, clientMgr, allNamespaces)
 	if err != nil {
 		return allHealthy, err
 	}
@@ -1,6 +1,8 @@
 package health
 
 import (
+	"context"
+
 	"github.com/ForgeRock/forgeops-cli/internal/k8s"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,7 +33,7 @@ type Resource struct {
 // a resource is checked in a namespace with the following priority
 // 1. Namespace on resource
 // 2. if 1. is nil then namespace on hlth
-func (r *Resource) Check(clientMgr k8s.ClientMgr, fallBackNamespace string) (bool, error) {
+func (r *Resource) Check(ctx context.Context, clientMgr k8s.ClientMgr, fallBackNamespace string) (bool, error) {
 	gvr := schema.GroupVersionResource{
 		Group:    r.Group,
 		Version:  r.APIVersion,
@@ -43,8 +45,7 @@ func (r *Resource) Check(clientMgr k8s.ClientMgr, fallBackNamespace string) (boo
 	}
 	passed := true
 	for _, check := range r.Checks {
-		// TODO WatchEventsForCondition should use a context
-		met, err := clientMgr.WatchEventsForCondition(int(check.Timeout.Seconds()), namespace, r.Name, gvr, k8s.ConditionExpression(check.Expression))
+		met, err := clientMgr.WatchEventsForCondition(ctx, int(check.Timeout.Seconds()), namespace, r.Name, gvr, k8s.ConditionExpression(check.Expression))
 		// A watch or condition not being met is failed, but not an _error_
 		if errors.Is(err, k8s.ErrWatchTimeout) {
 			passed = false
@@ -72,9 +73,23 @@ type Health struct {
 	healthy, unhealthy []string
 }
 
+// Unhealthy returns the resources that didn't pass their checks
+func (h *Health) Unhealthy() []*Resource {
+	unhealthy := []*Resource{}
+	for _, r := range h.Spec.Resources {
+		for _, name := range h.unhealthy {
+			if r.Name == name {
+				unhealthy = append(unhealthy, r)
+				break
+			}
+		}
+	}
+	return unhealthy
+}
+
 // CheckResources wait until all resources checks passed of have been exhausted
 // return true if all resources passed checks
-func (h *Health) CheckResources(client k8s.ClientMgr, allNamespaces bool) (bool, error) {
+func (h *Health) CheckResources(ctx context.Context, client k8s.ClientMgr, allNamespaces bool) (bool, error) {
 	// track reuslts
 	var err error = nil
 	for _, r := range h.Spec.Resources {
@@ -85,7 +100,7 @@ func (h *Health) CheckResources(client k8s.ClientMgr, allNamespaces bool) (bool,
 				err = errors.Wrapf(err, "%s checks failed", r.Name)
 			}
 		}
-		healthy, err := r.Check(client, ns)
+		healthy, err := r.Check(ctx, client, ns)
 		if err != nil {
 			//nolint
 			err = errors.Wrapf(err, "%s checks failed", r.Name)
@@ -1,6 +1,7 @@
 package health
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -119,6 +120,7 @@ func TestHealthyLogic(t *testing.T) {
 		//
 		for _, resource := range tc.resources {
 			testClientMgr.On("WatchEventsForCondition",
+				mock.Anything,
 				1,
 				"test_namespace",
 				resource.rname,
@@ -127,13 +129,22 @@ func TestHealthyLogic(t *testing.T) {
 			).Return(resource.conditionMet, resource.err)
 		}
 
-		res, resultErr := testHealth.CheckResources(testClientMgr, false)
+		res, resultErr := testHealth.CheckResources(context.Background(), testClientMgr, false)
 		if resultErr != tc.expectedErr {
 			t.Errorf("expected no error but found %s", resultErr.Error())
 		}
 		if res != tc.expect {
 			t.Error("expected check to pass")
 		}
+		unhealthy := 0
+		for _, r := range tc.resources {
+			if !r.conditionMet || r.err != nil {
+				unhealthy++
+			}
+		}
+		if found := len(testHealth.Unhealthy()); found != unhealthy {
+			t.Errorf("expected %d unhealthy resources, found %d", unhealthy, found)
+		}
 	}
 }
 
@@ -0,0 +1,55 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Custom installs the manifests in the given path, e.g. a kustomize overlay of the forgeops bases.
+// The objects are labeled with the component provided, which defaults to the name of the path
+func Custom(ctx context.Context, clientFactory factory.Factory, path, component string, pathOpts k8s.PathOptions, opts Options) error {
+	component, err := customComponent(path, component, profile.Current())
+	if err != nil {
+		return err
+	}
+	printer.NoticeHif("Installing %q from %q", component, path)
+	transforms := append(standardTransforms(component), ProvenanceTransform(path, "", ""))
+	if err := Manifest(ctx, clientFactory, path, pathOpts, opts, transforms...); err != nil {
+		return err
+	}
+	if opts.DryRun != DryRunNone {
+		printer.Noticef("Rendered %q from %q (%s dry run)", component, path, opts.DryRun)
+		return nil
+	}
+	printer.Noticef("Installed %q from %q", component, path)
+	return nil
+}
+
+// customComponent returns the component the objects in the path are labeled with. The component provided must be a
+// valid label value. The name of the path is only used when it's a valid label value that isn't a profile component,
+// so the objects of the custom install can't be mistaken for, or pruned with, the objects of the platform
+func customComponent(path, component string, p *profile.Profile) (string, error) {
+	if len(component) > 0 {
+		if errs := validation.IsValidLabelValue(component); len(errs) > 0 {
+			return "", fmt.Errorf("invalid component %q: %s", component, strings.Join(errs, "; "))
+		}
+		return component, nil
+	}
+	base := filepath.Base(filepath.Clean(path))
+	derived := strings.TrimSuffix(base, filepath.Ext(base))
+	if errs := validation.IsValidLabelValue(derived); len(derived) == 0 || len(errs) > 0 {
+		return "", fmt.Errorf("%q can't be used as the component of %q. Use --component to name it", derived, path)
+	}
+	if c, err := p.Component(derived); err == nil {
+		return "", fmt.Errorf("%q is the name of the %q component of the platform. Use --component to name the component of %q", derived, c.Name, path)
+	}
+	return derived, nil
+}
@@ -0,0 +1,39 @@
+package install
+
+import (
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+)
+
+// TestCustomComponent tests the component of custom installs can't be invalid or one of the platform components
+func TestCustomComponent(t *testing.T) {
+	p := profile.Current()
+	td := []struct {
+		// comment about test case
+		testComment string
+		path        string
+		component   string
+		expected    string
+		expectErr   bool
+	}{
+		{testComment: "the name of a directory is used", path: "./overlays/prod/", expected: "prod", expectErr: false},
+		{testComment: "the extension of a file is dropped", path: "manifests/monitoring.yaml", expected: "monitoring", expectErr: false},
+		{testComment: "the component provided is used", path: ".", component: "site", expected: "site", expectErr: false},
+		{testComment: "the current directory requires a component", path: ".", expectErr: true},
+		{testComment: "invalid label values require a component", path: "./my overlay", expectErr: true},
+		{testComment: "profile components require a component", path: "./base.yaml", expectErr: true},
+		{testComment: "profile component aliases require a component", path: "./ds", expectErr: true},
+		{testComment: "invalid components are rejected", path: "./base.yaml", component: "my site", expectErr: true},
+	}
+	for _, tc := range td {
+		component, err := customComponent(tc.path, tc.component, p)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+			continue
+		}
+		if err == nil && component != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, component)
+		}
+	}
+}
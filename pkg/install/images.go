@@ -0,0 +1,85 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// podSpecPaths location of the pod spec of each workload kind
+var podSpecPaths = map[string][]string{
+	"Deployment":  {"spec", "template", "spec"},
+	"StatefulSet": {"spec", "template", "spec"},
+	"Job":         {"spec", "template", "spec"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// ImageTransform rewrites the images of the containers and init containers of workloads.
+// Containers named in overrides use the image provided. The images of the other containers are pulled from the registry
+// provided instead of their original registry, e.g. gcr.io/forgerock-io/am:7.0.0 becomes <registry>/forgerock-io/am:7.0.0
+func ImageTransform(registry string, overrides map[string]string) TransformInfoFunc {
+	registry = strings.TrimSuffix(registry, "/")
+	return func(info *resource.Info) error {
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		path, ok := podSpecPaths[obj.GetKind()]
+		if !ok {
+			return nil
+		}
+		for _, containerType := range []string{"initContainers", "containers"} {
+			fields := append(append([]string{}, path...), containerType)
+			containers, found, err := unstructured.NestedSlice(obj.Object, fields...)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+			changed := false
+			for i, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := container["name"].(string)
+				image, _ := container["image"].(string)
+				newImage := image
+				if override, ok := overrides[name]; ok {
+					newImage = override
+				} else if len(registry) > 0 {
+					newImage = mirrorImage(registry, image)
+				}
+				if newImage == image {
+					continue
+				}
+				container["image"] = newImage
+				changed = true
+				reportSubstitution(info, []string{fmt.Sprintf("%s[%d].image", strings.Join(fields, "."), i)}, newImage)
+			}
+			if changed {
+				if err := unstructured.SetNestedSlice(obj.Object, containers, fields...); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// mirrorImage replaces the registry of the image with the registry provided.
+// Images without a registry, e.g. busybox, are Docker Hub images
+func mirrorImage(registry, image string) string {
+	if len(image) == 0 {
+		return image
+	}
+	parts := strings.SplitN(image, "/", 2)
+	// The first component is a registry host if it has a domain, a port or is localhost
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return registry + "/" + parts[1]
+	}
+	return registry + "/" + image
+}
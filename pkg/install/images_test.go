@@ -0,0 +1,94 @@
+package install
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+func TestMirrorImage(t *testing.T) {
+	tests := map[string]string{
+		"gcr.io/forgerock-io/am/pit1:7.1.0": "mirror.corp/forgerock/forgerock-io/am/pit1:7.1.0",
+		"localhost/am:dev":                  "mirror.corp/forgerock/am:dev",
+		"registry:5000/ds:7.0.0":            "mirror.corp/forgerock/ds:7.0.0",
+		"forgerock/ds":                      "mirror.corp/forgerock/forgerock/ds",
+		"busybox":                           "mirror.corp/forgerock/busybox",
+	}
+	for image, expected := range tests {
+		if got := mirrorImage("mirror.corp/forgerock", image); got != expected {
+			t.Errorf("mirrorImage(%q) = %q, expected %q", image, got, expected)
+		}
+	}
+}
+
+func TestImageTransform(t *testing.T) {
+	deployment := newTestInfo(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: am
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: fbc-init
+        image: gcr.io/forgerock-io/am-config-upgrader:7.0.0
+      containers:
+      - name: openam
+        image: gcr.io/forgerock-io/am:7.0.0
+`, meta.RESTScopeNamespace)
+	cronJob := newTestInfo(t, `
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: backup
+            image: gcr.io/forgerock-io/ds:7.0.0
+`, meta.RESTScopeNamespace)
+	configMap := newTestInfo(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: images
+data:
+  image: gcr.io/forgerock-io/am:7.0.0
+`, meta.RESTScopeNamespace)
+
+	tf := ImageTransform("mirror.corp/forgerock/", map[string]string{"openam": "repo/am:custom"})
+	for _, info := range []*resource.Info{deployment, cronJob, configMap} {
+		if err := tf(info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []struct {
+		info   *resource.Info
+		fields []string
+		image  string
+	}{
+		{deployment, []string{"spec", "template", "spec", "initContainers"}, "mirror.corp/forgerock/forgerock-io/am-config-upgrader:7.0.0"},
+		{deployment, []string{"spec", "template", "spec", "containers"}, "repo/am:custom"},
+		{cronJob, []string{"spec", "jobTemplate", "spec", "template", "spec", "containers"}, "mirror.corp/forgerock/forgerock-io/ds:7.0.0"},
+	}
+	for _, e := range expected {
+		containers, _, err := unstructured.NestedSlice(e.info.Object.(*unstructured.Unstructured).Object, e.fields...)
+		if err != nil || len(containers) != 1 {
+			t.Fatalf("expected 1 container in %v, found %d: %v", e.fields, len(containers), err)
+		}
+		if image := containers[0].(map[string]interface{})["image"]; image != e.image {
+			t.Errorf("expected image %q in %q, found %q", e.image, e.info.Name, image)
+		}
+	}
+	data, _, _ := unstructured.NestedStringMap(configMap.Object.(*unstructured.Unstructured).Object, "data")
+	if data["image"] != "gcr.io/forgerock-io/am:7.0.0" {
+		t.Errorf("expected objects other than workloads to be left untouched, found %q", data["image"])
+	}
+}
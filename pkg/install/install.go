@@ -1,60 +1,173 @@
 package install
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/pkg/diagnose"
+	"github.com/ForgeRock/forgeops-cli/pkg/sizing"
 	"github.com/ForgeRock/forgeops-cli/pkg/version"
 	"k8s.io/apimachinery/pkg/api/meta"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
 )
 
 // TransformInfoFunc is used to modify the resource.Info
 type TransformInfoFunc func(*resource.Info) error
 
+// DryRunStrategy determines how a dry run is performed
+type DryRunStrategy string
+
+var (
+	// DryRunNone objects are applied
+	DryRunNone DryRunStrategy = ""
+	// DryRunClient objects are printed instead of applied
+	DryRunClient DryRunStrategy = "client"
+	// DryRunServer objects are sent to the API server with dryRun=All and are not persisted
+	DryRunServer DryRunStrategy = "server"
+)
+
+// ParseDryRunStrategy validates the value of the --dry-run flag
+func ParseDryRunStrategy(s string) (DryRunStrategy, error) {
+	switch DryRunStrategy(s) {
+	case DryRunNone, DryRunClient, DryRunServer:
+		return DryRunStrategy(s), nil
+	}
+	return DryRunNone, fmt.Errorf("invalid dry-run value %q. Allowed values are \"client\" and \"server\"", s)
+}
+
+// ConfigureDryRunOutput leaves stdout to the manifests of a client dry run so they can be reviewed or piped to kubectl.
+// The notices are written to stderr
+func ConfigureDryRunOutput(strategy DryRunStrategy) {
+	if strategy == DryRunClient {
+		printer.SetConsoleOutput(os.Stderr)
+	}
+}
+
+// Options controls how manifests are installed
+type Options struct {
+	// DryRun runs the install pipeline without persisting any object
+	DryRun DryRunStrategy
+	// ForceConflicts takes ownership of fields owned by other field managers, e.g. operators
+	ForceConflicts bool
+	// Prune deletes the objects of the applied components that were removed from the manifest
+	Prune bool
+	// ImageRegistry registry the workload images are pulled from instead of their original registry
+	ImageRegistry string
+	// SetImages images used by the containers with the given names
+	SetImages map[string]string
+	// Size replicas, resources and storage of the workloads. The release manifests are left untouched when nil
+	Size *sizing.Size
+	// Labels added to every object
+	Labels map[string]string
+	// Annotations added to every object
+	Annotations map[string]string
+	// PropagateMetadata also adds the labels and annotations to the pod templates of the workloads
+	PropagateMetadata bool
+	// Resume continues the quickstart from the first step that didn't complete
+	Resume bool
+	// Diagnostics controls the diagnosis collected when a wait fails
+	Diagnostics diagnose.Options
+	// Timeout overall budget of the waits. The waits aren't bounded by an overall budget when 0
+	Timeout time.Duration
+	// WaitTimeouts timeouts of the wait stages replacing the timeouts of the profile, e.g. ds=20m
+	WaitTimeouts map[string]time.Duration
+
+	// deadline of the overall budget, set when the quickstart or the rollback starts
+	deadline time.Time
+	// rollback the components are installed by a rollback
+	rollback bool
+	// liveStorage storage of the volume claim templates of the StatefulSets being upgraded, kept as they are immutable
+	liveStorage claimStorage
+}
+
+// startTimeout starts the overall budget of the waits at the given time. A budget already started is kept
+func (o Options) startTimeout(now time.Time) Options {
+	if o.Timeout > 0 && o.deadline.IsZero() {
+		o.deadline = now.Add(o.Timeout)
+	}
+	return o
+}
+
+// transforms provides the transforms requested in the options
+func (o Options) transforms() []TransformInfoFunc {
+	tfs := []TransformInfoFunc{}
+	if len(o.ImageRegistry) > 0 || len(o.SetImages) > 0 {
+		tfs = append(tfs, ImageTransform(o.ImageRegistry, o.SetImages))
+	}
+	if o.Size != nil {
+		tfs = append(tfs, SizeTransform(*o.Size))
+	}
+	if len(o.liveStorage) > 0 {
+		tfs = append(tfs, keepClaimStorage(o.liveStorage))
+	}
+	if len(o.Labels) > 0 || len(o.Annotations) > 0 {
+		tfs = append(tfs, MetadataTransform(o.Labels, o.Annotations, o.PropagateMetadata))
+	}
+	return tfs
+}
+
 // Manifest obtains the manifest from the given path or URL and applies it in the namespace provided
-func Manifest(clientFactory factory.Factory, path string, transformFunctions ...TransformInfoFunc) error {
+func Manifest(ctx context.Context, clientFactory factory.Factory, path string, pathOpts k8s.PathOptions, opts Options, transformFunctions ...TransformInfoFunc) error {
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
-	infos, err := k8sCntMgr.GetObjectsFromPath(path)
+	infos, err := k8sCntMgr.GetObjectsFromPath(path, pathOpts)
 	if err != nil {
 		return err
 	}
-	return Resources(clientFactory, infos, transformFunctions...)
+	return Resources(ctx, clientFactory, infos, opts, transformFunctions...)
 }
 
 // ManifestStr Applies the given manifest in the namespace provided
-func ManifestStr(clientFactory factory.Factory, manifestContents string, transformFunctions ...TransformInfoFunc) error {
+func ManifestStr(ctx context.Context, clientFactory factory.Factory, manifestContents string, opts Options, transformFunctions ...TransformInfoFunc) error {
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	infos, err := k8sCntMgr.GetObjectsFromStream(strings.NewReader(manifestContents))
 	if err != nil {
 		return err
 	}
-	return Resources(clientFactory, infos, transformFunctions...)
+	return Resources(ctx, clientFactory, infos, opts, transformFunctions...)
+}
+
+// Render obtains the objects in the given manifest and applies the transforms to them
+func Render(clientFactory factory.Factory, manifestContents string, transformFunctions ...TransformInfoFunc) ([]*resource.Info, error) {
+	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
+	infos, err := k8sCntMgr.GetObjectsFromStream(strings.NewReader(manifestContents))
+	if err != nil {
+		return nil, err
+	}
+	if err := transform(infos, transformFunctions...); err != nil {
+		return nil, err
+	}
+	return infos, nil
 }
 
 // Resources applies the resources provided
-func Resources(clientFactory factory.Factory, infos []*resource.Info, transformFunctions ...TransformInfoFunc) error {
+func Resources(ctx context.Context, clientFactory factory.Factory, infos []*resource.Info, opts Options, transformFunctions ...TransformInfoFunc) error {
 	errs := []error{}
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	if len(infos) == 0 {
 		return fmt.Errorf("no objects found")
 	}
-	for _, tf := range transformFunctions {
-		for _, info := range infos {
-			if err := tf(info); err != nil {
-				return err
-			}
-		}
+	if err := transform(infos, append(transformFunctions, opts.transforms()...)...); err != nil {
+		return err
 	}
-	// Iterate through all objects, applying each one.
-	for _, info := range infos {
-		if err := k8sCntMgr.ApplyObject(info); err != nil {
-			errs = append(errs, err)
+	if opts.DryRun == DryRunClient {
+		if err := printResources(infos); err != nil {
+			return err
 		}
+		if opts.Prune {
+			return prune(ctx, k8sCntMgr, infos, opts)
+		}
+		return nil
 	}
+	// Apply the objects in kind order so their dependencies, e.g. CRDs, are applied first
+	errs = append(errs, applyOrdered(ctx, k8sCntMgr, infos, opts)...)
 	// If any errors occurred during apply, then return error (or
 	// aggregate of errors).
 	if len(errs) == 1 {
@@ -63,11 +176,38 @@ func Resources(clientFactory factory.Factory, infos []*resource.Info, transformF
 	if len(errs) > 1 {
 		return utilerrors.NewAggregate(errs)
 	}
+	// Only prune once the whole manifest has been applied
+	if opts.Prune {
+		return prune(ctx, k8sCntMgr, infos, opts)
+	}
+	return nil
+}
+
+func transform(infos []*resource.Info, transformFunctions ...TransformInfoFunc) error {
+	for _, tf := range transformFunctions {
+		for _, info := range infos {
+			if err := tf(info); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// printResources prints the objects as a multi-document YAML manifest
+func printResources(infos []*resource.Info) error {
+	for _, info := range infos {
+		data, err := yaml.Marshal(info.Object)
+		if err != nil {
+			return err
+		}
+		printer.Document("---\n" + strings.TrimSuffix(string(data), "\n"))
+	}
 	return nil
 }
 
-// Provides a set of standard transforms applied to resource.Info objects
-func standardTransforms() []TransformInfoFunc {
+// Provides a set of standard transforms applied to resource.Info objects of the given component
+func standardTransforms(component string) []TransformInfoFunc {
 
 	manageLabels := func(info *resource.Info) error {
 		var metadataAccessor = meta.NewAccessor()
@@ -78,7 +218,8 @@ func standardTransforms() []TransformInfoFunc {
 		if labels == nil {
 			labels = make(map[string]string)
 		}
-		labels["forgeops-cli.forgerock.com/version"] = version.Version
+		labels[VersionLabel] = version.Version
+		labels[ComponentLabel] = component
 		metadataAccessor.SetLabels(info.Object, labels)
 		return nil
 	}
@@ -0,0 +1,118 @@
+package install
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// TestParseDryRunStrategy tests the values of the --dry-run flag
+func TestParseDryRunStrategy(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		value       string
+		expected    DryRunStrategy
+		expectErr   bool
+	}{
+		{testComment: "client prints the objects", value: "client", expected: DryRunClient, expectErr: false},
+		{testComment: "server submits the objects with dryRun=All", value: "server", expected: DryRunServer, expectErr: false},
+		{testComment: "no value applies the objects", value: "", expected: DryRunNone, expectErr: false},
+		{testComment: "invalid values are rejected", value: "true", expected: DryRunNone, expectErr: true},
+		{testComment: "values are case sensitive", value: "Server", expected: DryRunNone, expectErr: true},
+	}
+	for _, tc := range td {
+		strategy, err := ParseDryRunStrategy(tc.value)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+		}
+		if strategy != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, strategy)
+		}
+	}
+}
+
+// captureOutput redirects stdout and stderr to files until the test completes
+func captureOutput(t *testing.T) (stdout, stderr *os.File) {
+	dir := t.TempDir()
+	stdout, err := ioutil.TempFile(dir, "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err = ioutil.TempFile(dir, "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = origStdout, origStderr
+		printer.SetConsoleOutput(os.Stdout)
+		stdout.Close()
+		stderr.Close()
+	})
+	return stdout, stderr
+}
+
+// TestClientDryRunOutput tests stdout only carries the manifests of a client dry run, so they can be piped to kubectl
+func TestClientDryRunOutput(t *testing.T) {
+	stdout, stderr := captureOutput(t)
+	// The notices are printed to stdout by default
+	printer.SetConsoleOutput(os.Stdout)
+	ConfigureDryRunOutput(DryRunClient)
+	infos := []*resource.Info{
+		newTestInfo(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: am
+  namespace: default
+`, meta.RESTScopeNamespace),
+		newTestInfo(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: platform-config
+data:
+  FQDN: default.iam.example.com
+`, meta.RESTScopeNamespace),
+	}
+	if err := setNamespaces(infos, "default", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Resources(context.Background(), nil, infos, Options{DryRun: DryRunClient}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stdout.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	decoder := yaml.NewYAMLOrJSONDecoder(stdout, 4096)
+	names := []string{}
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("expected stdout to be a YAML manifest, found: %+v", err)
+		}
+		if len(obj.GetKind()) == 0 || obj.GetNamespace() != "prod" {
+			t.Fatalf("expected only the objects on stdout, found: %v", obj.Object)
+		}
+		names = append(names, obj.GetName())
+	}
+	if len(names) != 2 || names[0] != "am" || names[1] != "platform-config" {
+		t.Errorf("expected the manifests of am and platform-config, found: %v", names)
+	}
+	if notices, err := ioutil.ReadFile(stderr.Name()); err != nil || len(notices) == 0 {
+		t.Errorf("expected the notices on stderr, found: %q, %+v", notices, err)
+	}
+}
@@ -0,0 +1,57 @@
+package install
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
+	"github.com/ForgeRock/forgeops-cli/pkg/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// ReleaseManifest the objects of a release manifest rendered for the target cluster
+type ReleaseManifest struct {
+	GHRepo   string
+	FileName string
+	Version  string
+	// Checksum sha256 of the manifest as published in the release
+	Checksum string
+	// FQDN the deployment is rendered for. Empty for manifests without FQDN placeholders
+	FQDN  string
+	Infos []*resource.Info
+}
+
+func newReleaseManifest(ghRepo, fileName, version, manifestStr string) *ReleaseManifest {
+	sum := sha256.Sum256([]byte(manifestStr))
+	return &ReleaseManifest{
+		GHRepo:   ghRepo,
+		FileName: fileName,
+		Version:  version,
+		Checksum: hex.EncodeToString(sum[:]),
+	}
+}
+
+// recordInventory stores what was installed from the release manifest in the given namespace
+func recordInventory(ctx context.Context, clientFactory factory.Factory, ns string, rm *ReleaseManifest, opts Options) error {
+	return inventory.Write(ctx, clientFactory, inventoryRecord(ns, rm, opts, metav1.Now()))
+}
+
+// inventoryRecord describes what was installed from the release manifest at the given time
+func inventoryRecord(ns string, rm *ReleaseManifest, opts Options, installedAt metav1.Time) inventory.Record {
+	return inventory.Record{
+		Namespace:   ns,
+		Component:   inventory.ComponentName(rm.FileName),
+		GHRepo:      rm.GHRepo,
+		Manifest:    rm.FileName,
+		Version:     rm.Version,
+		Checksum:    rm.Checksum,
+		FQDN:        rm.FQDN,
+		CLIVersion:  version.Version,
+		InstalledAt: installedAt,
+		Objects:     inventory.ObjectRefs(rm.Infos),
+		RolledBack:  opts.rollback,
+	}
+}
@@ -0,0 +1,146 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	// reservedPrefix prefix of the labels and annotations managed by the forgeops-cli
+	reservedPrefix = "forgeops-cli.forgerock.com/"
+	// SourceAnnotation location the manifest of an object was read from
+	SourceAnnotation = "forgeops-cli.forgerock.com/source"
+	// TagAnnotation release tag of the manifest of an object
+	TagAnnotation = "forgeops-cli.forgerock.com/tag"
+	// ChecksumAnnotation sha256 of the manifest of an object
+	ChecksumAnnotation = "forgeops-cli.forgerock.com/manifest-sha256"
+	// ReleaseLabel release tag of the manifest of an object, e.g. to select the objects installed from a release
+	ReleaseLabel = "forgeops-cli.forgerock.com/release"
+)
+
+// ValidateMetadata checks the labels and annotations provided by users are valid and aren't managed by the forgeops-cli
+func ValidateMetadata(labels, annotations map[string]string) error {
+	for k, v := range labels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid label %q: %s", k, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid value of label %q: %s", k, strings.Join(errs, ", "))
+		}
+	}
+	for k := range annotations {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation %q: %s", k, strings.Join(errs, ", "))
+		}
+	}
+	for _, m := range []map[string]string{labels, annotations} {
+		for k := range m {
+			if strings.HasPrefix(k, reservedPrefix) {
+				return fmt.Errorf("%q is managed by the forgeops-cli. Keys with the prefix %q can't be set", k, reservedPrefix)
+			}
+		}
+	}
+	return nil
+}
+
+// MetadataTransform adds the labels and annotations to every object.
+// When propagate is true they're also added to the pod templates of the workloads
+func MetadataTransform(labels, annotations map[string]string, propagate bool) TransformInfoFunc {
+	return func(info *resource.Info) error {
+		var metadataAccessor = meta.NewAccessor()
+		if err := addMetadata(metadataAccessor, info.Object, labels, annotations); err != nil {
+			return err
+		}
+		if !propagate {
+			return nil
+		}
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		path, ok := podSpecPaths[obj.GetKind()]
+		if !ok {
+			return nil
+		}
+		// The pod template metadata is next to the pod spec
+		metadataPath := append(append([]string{}, path[:len(path)-1]...), "metadata")
+		for field, values := range map[string]map[string]string{"labels": labels, "annotations": annotations} {
+			if len(values) == 0 {
+				continue
+			}
+			fields := append(append([]string{}, metadataPath...), field)
+			current, _, err := unstructured.NestedStringMap(obj.Object, fields...)
+			if err != nil {
+				return err
+			}
+			if current == nil {
+				current = map[string]string{}
+			}
+			for k, v := range values {
+				current[k] = v
+			}
+			if err := unstructured.SetNestedStringMap(obj.Object, current, fields...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ProvenanceTransform annotates every object with the location, tag and checksum of its manifest.
+// The tag is also added as the ReleaseLabel when it's a valid label value. Empty values aren't added
+func ProvenanceTransform(source, tag, checksum string) TransformInfoFunc {
+	annotations := map[string]string{}
+	for k, v := range map[string]string{SourceAnnotation: source, TagAnnotation: tag, ChecksumAnnotation: checksum} {
+		if len(v) > 0 {
+			annotations[k] = v
+		}
+	}
+	labels := map[string]string{}
+	if len(tag) > 0 && len(validation.IsValidLabelValue(tag)) == 0 {
+		labels[ReleaseLabel] = tag
+	}
+	return func(info *resource.Info) error {
+		return addMetadata(meta.NewAccessor(), info.Object, labels, annotations)
+	}
+}
+
+func addMetadata(metadataAccessor meta.MetadataAccessor, obj runtime.Object, labels, annotations map[string]string) error {
+	if len(labels) > 0 {
+		current, err := metadataAccessor.Labels(obj)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			current = make(map[string]string)
+		}
+		for k, v := range labels {
+			current[k] = v
+		}
+		if err := metadataAccessor.SetLabels(obj, current); err != nil {
+			return err
+		}
+	}
+	if len(annotations) > 0 {
+		current, err := metadataAccessor.Annotations(obj)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			current = make(map[string]string)
+		}
+		for k, v := range annotations {
+			current[k] = v
+		}
+		if err := metadataAccessor.SetAnnotations(obj, current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package install
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	if err := ValidateMetadata(map[string]string{"team": "iam", "example.com/env": "stage"}, map[string]string{"cost-center": "12 34"}); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateMetadata(map[string]string{"team": "not valid"}, nil); err == nil {
+		t.Error("expected an error for an invalid label value")
+	}
+	if err := ValidateMetadata(nil, map[string]string{ChecksumAnnotation: "abc"}); err == nil {
+		t.Error("expected an error for an annotation managed by the forgeops-cli")
+	}
+}
+
+func TestMetadataTransform(t *testing.T) {
+	deployment := newTestInfo(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: am
+  labels:
+    app: am
+spec:
+  template:
+    metadata:
+      labels:
+        app: am
+    spec:
+      containers:
+      - name: openam
+`, meta.RESTScopeNamespace)
+	service := newTestInfo(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: am
+`, meta.RESTScopeNamespace)
+
+	tf := MetadataTransform(map[string]string{"team": "iam"}, map[string]string{"cost-center": "1234"}, true)
+	if err := tf(deployment); err != nil {
+		t.Fatal(err)
+	}
+	if err := tf(service); err != nil {
+		t.Fatal(err)
+	}
+	if err := ProvenanceTransform("https://example.com/am.yaml", "v1", "")(service); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := deployment.Object.(*unstructured.Unstructured)
+	if obj.GetLabels()["team"] != "iam" || obj.GetLabels()["app"] != "am" {
+		t.Errorf("expected the labels to be added, found %v", obj.GetLabels())
+	}
+	podLabels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+	if podLabels["team"] != "iam" || podLabels["app"] != "am" {
+		t.Errorf("expected the labels to be propagated to the pod template, found %v", podLabels)
+	}
+	podAnnotations, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "annotations")
+	if podAnnotations["cost-center"] != "1234" {
+		t.Errorf("expected the annotations to be propagated to the pod template, found %v", podAnnotations)
+	}
+
+	annotations := service.Object.(*unstructured.Unstructured).GetAnnotations()
+	if annotations["cost-center"] != "1234" || annotations[SourceAnnotation] != "https://example.com/am.yaml" || annotations[TagAnnotation] != "v1" {
+		t.Errorf("unexpected annotations %v", annotations)
+	}
+	if _, found := annotations[ChecksumAnnotation]; found {
+		t.Error("expected empty provenance values to be left out")
+	}
+	if release := service.Object.(*unstructured.Unstructured).GetLabels()[ReleaseLabel]; release != "v1" {
+		t.Errorf("expected the release label to be added, found %q", release)
+	}
+}
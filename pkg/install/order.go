@@ -0,0 +1,137 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// crdEstablishedTimeout seconds to wait for a CRD to be established
+const crdEstablishedTimeout = 60
+
+// kindPriorities apply order of the kinds. Kinds not listed are applied with the workloads,
+// custom resources are applied last
+var kindPriorities = map[string]int{
+	"Namespace":                0,
+	"CustomResourceDefinition": 0,
+	"ServiceAccount":           1,
+	"Role":                     1,
+	"ClusterRole":              1,
+	"RoleBinding":              1,
+	"ClusterRoleBinding":       1,
+	"ConfigMap":                2,
+	"Secret":                   2,
+}
+
+const (
+	workloadPriority       = 3
+	customResourcePriority = 4
+)
+
+// kindPriority returns the apply order of the object
+func kindPriority(info *resource.Info) int {
+	gvk := info.Object.GetObjectKind().GroupVersionKind()
+	if !builtinGroup(gvk.Group) {
+		return customResourcePriority
+	}
+	if p, ok := kindPriorities[gvk.Kind]; ok {
+		return p
+	}
+	return workloadPriority
+}
+
+// builtinGroup is true for the API groups served by Kubernetes, e.g. apps or rbac.authorization.k8s.io
+func builtinGroup(group string) bool {
+	return !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io")
+}
+
+// orderByKind sorts the objects in apply order: Namespaces and CRDs, RBAC, ConfigMaps and Secrets,
+// workloads and the other Kubernetes kinds, and then custom resources. The manifest order is kept within each group
+func orderByKind(infos []*resource.Info) []*resource.Info {
+	ordered := append([]*resource.Info{}, infos...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return kindPriority(ordered[i]) < kindPriority(ordered[j])
+	})
+	return ordered
+}
+
+// applyOrdered applies the objects in kind order. The CRDs applied must be established before the objects after them
+// are applied. Custom resources of those CRDs are mapped once the CRDs are established
+func applyOrdered(ctx context.Context, k8sCntMgr k8s.ClientMgr, infos []*resource.Info, opts Options) []error {
+	applyOpts := k8s.ApplyOptions{
+		DryRun:         opts.DryRun == DryRunServer,
+		ForceConflicts: opts.ForceConflicts,
+	}
+	ordered := orderByKind(infos)
+	split := 0
+	for split < len(ordered) && kindPriority(ordered[split]) == 0 {
+		split++
+	}
+	errs := applyAll(ctx, k8sCntMgr, ordered[:split], applyOpts)
+	crds := []*resource.Info{}
+	for _, info := range ordered[:split] {
+		if info.Object.GetObjectKind().GroupVersionKind().Kind == "CustomResourceDefinition" {
+			crds = append(crds, info)
+		}
+	}
+	// CRDs aren't persisted in dry runs. Their custom resources are reported as skipped
+	if len(crds) > 0 && len(errs) == 0 && opts.DryRun == DryRunNone {
+		if err := establishCRDs(ctx, k8sCntMgr, crds, ordered[split:]); err != nil {
+			return append(errs, err)
+		}
+	}
+	return append(errs, applyAll(ctx, k8sCntMgr, ordered[split:], applyOpts)...)
+}
+
+func applyAll(ctx context.Context, k8sCntMgr k8s.ClientMgr, infos []*resource.Info, applyOpts k8s.ApplyOptions) []error {
+	errs := []error{}
+	for _, info := range infos {
+		if info.Mapping == nil {
+			kind := info.Object.GetObjectKind().GroupVersionKind().Kind
+			if applyOpts.DryRun {
+				printer.Warnf("Skipping %s %q (server dry run). Its CustomResourceDefinition isn't installed", kind, info.Name)
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s %q can't be applied. Its CustomResourceDefinition isn't established", kind, info.Name))
+			continue
+		}
+		if err := k8sCntMgr.ApplyObject(ctx, info, applyOpts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// establishCRDs waits for the CRDs to be established and maps the custom resources in dependents
+func establishCRDs(ctx context.Context, k8sCntMgr k8s.ClientMgr, crds []*resource.Info, dependents []*resource.Info) error {
+	gvr := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	for _, crd := range crds {
+		printer.Noticef("Waiting for CustomResourceDefinition %q to be established", crd.Name)
+		established, err := k8sCntMgr.WaitForResourceStatusCondition(ctx, crdEstablishedTimeout, "", crd.Name, "Established", gvr)
+		if err != nil {
+			return fmt.Errorf("CustomResourceDefinition %q wasn't established: %w", crd.Name, err)
+		}
+		if !established {
+			return fmt.Errorf("CustomResourceDefinition %q wasn't established", crd.Name)
+		}
+	}
+	unmapped := []*resource.Info{}
+	for _, info := range dependents {
+		if info.Mapping == nil {
+			unmapped = append(unmapped, info)
+		}
+	}
+	if len(unmapped) == 0 {
+		return nil
+	}
+	if err := k8sCntMgr.ResetRESTMapper(); err != nil {
+		return err
+	}
+	return k8sCntMgr.MapObjects(unmapped)
+}
@@ -0,0 +1,93 @@
+package install
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/resource"
+
+	imock "github.com/ForgeRock/forgeops-cli/internal/mock"
+)
+
+func names(infos []*resource.Info) []string {
+	result := []string{}
+	for _, info := range infos {
+		result = append(result, info.Name)
+	}
+	return result
+}
+
+// TestOrderByKind tests objects are sorted by kind and keep the manifest order within each kind
+func TestOrderByKind(t *testing.T) {
+	infos := []*resource.Info{
+		newTestInfo(t, `{apiVersion: directory.forgerock.io/v1alpha1, kind: DirectoryService, metadata: {name: ds-idrepo}}`, nil),
+		newTestInfo(t, `{apiVersion: apps/v1, kind: Deployment, metadata: {name: am}}`, meta.RESTScopeNamespace),
+		newTestInfo(t, `{apiVersion: v1, kind: Service, metadata: {name: am}}`, meta.RESTScopeNamespace),
+		newTestInfo(t, `{apiVersion: v1, kind: Secret, metadata: {name: am-env-secrets}}`, meta.RESTScopeNamespace),
+		newTestInfo(t, `{apiVersion: rbac.authorization.k8s.io/v1, kind: RoleBinding, metadata: {name: ds-operator}}`, meta.RESTScopeNamespace),
+		newTestInfo(t, `{apiVersion: v1, kind: ConfigMap, metadata: {name: platform-config}}`, meta.RESTScopeNamespace),
+		newTestInfo(t, `{apiVersion: apiextensions.k8s.io/v1, kind: CustomResourceDefinition, metadata: {name: directoryservices.directory.forgerock.io}}`, meta.RESTScopeNamespace),
+		newTestInfo(t, `{apiVersion: v1, kind: ServiceAccount, metadata: {name: ds-operator}}`, meta.RESTScopeNamespace),
+		newTestInfo(t, `{apiVersion: v1, kind: Namespace, metadata: {name: fr-system}}`, meta.RESTScopeNamespace),
+	}
+	expected := []string{
+		"directoryservices.directory.forgerock.io", "fr-system",
+		"ds-operator", "ds-operator",
+		"am-env-secrets", "platform-config",
+		"am", "am",
+		"ds-idrepo",
+	}
+	ordered := names(orderByKind(infos))
+	for i := range expected {
+		if ordered[i] != expected[i] {
+			t.Fatalf("expected order %v, found %v", expected, ordered)
+		}
+	}
+	if infos[0].Name != "ds-idrepo" {
+		t.Error("expected the objects provided to be left untouched")
+	}
+}
+
+// TestApplyOrdered tests custom resources are mapped and applied once their CRD is established
+func TestApplyOrdered(t *testing.T) {
+	crd := newTestInfo(t, `{apiVersion: apiextensions.k8s.io/v1, kind: CustomResourceDefinition, metadata: {name: directoryservices.directory.forgerock.io}}`, meta.RESTScopeNamespace)
+	ds := newTestInfo(t, `{apiVersion: directory.forgerock.io/v1alpha1, kind: DirectoryService, metadata: {name: ds-idrepo}}`, nil)
+	secret := newTestInfo(t, `{apiVersion: v1, kind: Secret, metadata: {name: ds-passwords}}`, meta.RESTScopeNamespace)
+
+	applied := []string{}
+	testClientMgr := &imock.ClientMgr{}
+	testClientMgr.On("ApplyObject", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			applied = append(applied, args.Get(1).(*resource.Info).Name)
+		}).
+		Return(nil)
+	testClientMgr.On("WaitForResourceStatusCondition", mock.Anything, crdEstablishedTimeout, "", crd.Name, "Established", mock.Anything).
+		Return(true, nil)
+	testClientMgr.On("ResetRESTMapper").Return(nil)
+	testClientMgr.On("MapObjects", []*resource.Info{ds}).
+		Run(func(args mock.Arguments) {
+			ds.Mapping = &meta.RESTMapping{Scope: meta.RESTScopeNamespace}
+		}).
+		Return(nil)
+
+	if errs := applyOrdered(context.Background(), testClientMgr, []*resource.Info{ds, secret, crd}, Options{}); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	expected := []string{crd.Name, secret.Name, ds.Name}
+	if len(applied) != len(expected) || applied[0] != expected[0] || applied[1] != expected[1] || applied[2] != expected[2] {
+		t.Errorf("expected apply order %v, found %v", expected, applied)
+	}
+	testClientMgr.AssertCalled(t, "ResetRESTMapper")
+
+	// CRDs aren't persisted during dry runs, their custom resources are skipped
+	unmapped := newTestInfo(t, `{apiVersion: directory.forgerock.io/v1alpha1, kind: DirectoryService, metadata: {name: ds-cts}}`, nil)
+	dryRunClientMgr := &imock.ClientMgr{}
+	dryRunClientMgr.On("ApplyObject", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	if errs := applyOrdered(context.Background(), dryRunClientMgr, []*resource.Info{unmapped, crd}, Options{DryRun: DryRunServer}); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	dryRunClientMgr.AssertNumberOfCalls(t, "ApplyObject", 1)
+	dryRunClientMgr.AssertNotCalled(t, "WaitForResourceStatusCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,120 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/pkg/diagnose"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// podsRefresh interval the pods of the awaited object are listed at
+const podsRefresh = 5 * time.Second
+
+// waitProgress tracks the state of an awaited object from its watch events and the phases of its pods
+type waitProgress struct {
+	// listPods lists the pods of the awaited object
+	listPods     func(obj *unstructured.Unstructured) ([]corev1.Pod, error)
+	mu           sync.Mutex
+	obj          *unstructured.Unstructured
+	replicas     string
+	pods         string
+	podsListedAt time.Time
+}
+
+func newWaitProgress(ctx context.Context, clientFactory factory.Factory, gvr schema.GroupVersionResource) *waitProgress {
+	return &waitProgress{listPods: func(obj *unstructured.Unstructured) ([]corev1.Pod, error) {
+		return diagnose.Pods(ctx, clientFactory, gvr, obj)
+	}}
+}
+
+// observe records the object of each event evaluated by the condition
+func (w *waitProgress) observe(condition k8s.ConditionFunction) k8s.ConditionFunction {
+	return func(event watch.Event, obj *unstructured.Unstructured) (bool, error) {
+		if obj != nil && event.Type != watch.Deleted {
+			w.mu.Lock()
+			w.obj = obj
+			w.replicas = replicaSummary(obj)
+			w.mu.Unlock()
+		}
+		return condition(event, obj)
+	}
+}
+
+// status describes the replicas of the object and the phases of its pods, e.g. ready 0/1, pods Pending=1.
+// The pods are listed without holding the lock so the watch events aren't held up by the API server
+func (w *waitProgress) status() string {
+	w.mu.Lock()
+	obj := w.obj
+	refresh := obj != nil && time.Since(w.podsListedAt) >= podsRefresh
+	if refresh {
+		w.podsListedAt = time.Now()
+	}
+	w.mu.Unlock()
+	if obj == nil {
+		return "not created yet"
+	}
+	if refresh {
+		// The progress is informative, pods that can't be listed are left out
+		if pods, err := w.listPods(obj); err == nil {
+			summary := podPhaseSummary(pods)
+			w.mu.Lock()
+			w.pods = summary
+			w.mu.Unlock()
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	parts := []string{}
+	for _, p := range []string{w.replicas, w.pods} {
+		if len(p) > 0 {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// replicaSummary describes the ready and desired replicas of workloads, or the completions of jobs
+func replicaSummary(obj *unstructured.Unstructured) string {
+	if desired, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		return fmt.Sprintf("ready %d/%d", ready, desired)
+	}
+	if obj.GetKind() == "Job" {
+		completions, found, _ := unstructured.NestedInt64(obj.Object, "spec", "completions")
+		if !found {
+			completions = 1
+		}
+		succeeded, _, _ := unstructured.NestedInt64(obj.Object, "status", "succeeded")
+		active, _, _ := unstructured.NestedInt64(obj.Object, "status", "active")
+		failed, _, _ := unstructured.NestedInt64(obj.Object, "status", "failed")
+		return fmt.Sprintf("succeeded %d/%d, active %d, failed %d", succeeded, completions, active, failed)
+	}
+	return ""
+}
+
+// podPhaseSummary counts the pods in each phase, e.g. pods Pending=1 Running=2
+func podPhaseSummary(pods []corev1.Pod) string {
+	if len(pods) == 0 {
+		return "no pods"
+	}
+	counts := map[string]int{}
+	for _, pod := range pods {
+		counts[string(pod.Status.Phase)]++
+	}
+	phases := []string{}
+	for phase, count := range counts {
+		phases = append(phases, fmt.Sprintf("%s=%d", phase, count))
+	}
+	sort.Strings(phases)
+	return "pods " + strings.Join(phases, " ")
+}
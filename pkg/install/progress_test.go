@@ -0,0 +1,138 @@
+package install
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// TestReplicaSummary tests the replicas of workloads and the completions of jobs are described
+func TestReplicaSummary(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		obj         map[string]interface{}
+		expected    string
+	}{
+		{
+			testComment: "statefulsets report their ready replicas",
+			obj: map[string]interface{}{
+				"kind":   "StatefulSet",
+				"spec":   map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{"readyReplicas": int64(1)},
+			},
+			expected: "ready 1/2",
+		},
+		{
+			testComment: "jobs report their completions",
+			obj: map[string]interface{}{
+				"kind":   "Job",
+				"spec":   map[string]interface{}{},
+				"status": map[string]interface{}{"active": int64(1)},
+			},
+			expected: "succeeded 0/1, active 1, failed 0",
+		},
+		{
+			testComment: "other kinds aren't described",
+			obj:         map[string]interface{}{"kind": "Secret"},
+			expected:    "",
+		},
+	}
+	for _, tc := range td {
+		if found := replicaSummary(&unstructured.Unstructured{Object: tc.obj}); found != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, found)
+		}
+	}
+}
+
+// TestPodPhaseSummary tests pods are counted by phase
+func TestPodPhaseSummary(t *testing.T) {
+	pods := []corev1.Pod{
+		{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		{Status: corev1.PodStatus{Phase: corev1.PodPending}},
+		{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+	}
+	if found := podPhaseSummary(pods); found != "pods Pending=1 Running=2" {
+		t.Errorf("expected pods Pending=1 Running=2, found: %q", found)
+	}
+	if found := podPhaseSummary(nil); found != "no pods" {
+		t.Errorf("expected no pods, found: %q", found)
+	}
+}
+
+// TestWaitProgressObserve tests the status follows the objects evaluated by the condition
+func TestWaitProgressObserve(t *testing.T) {
+	wp := newWaitProgress(context.Background(), nil, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+	if found := wp.status(); found != "not created yet" {
+		t.Errorf("expected not created yet, found: %q", found)
+	}
+	// The pods were just listed, they aren't listed again
+	wp.podsListedAt = time.Now()
+	wp.pods = "pods Running=1"
+	evaluated := false
+	condition := wp.observe(func(event watch.Event, obj *unstructured.Unstructured) (bool, error) {
+		evaluated = true
+		return false, nil
+	})
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":   "Deployment",
+		"spec":   map[string]interface{}{"replicas": int64(1)},
+		"status": map[string]interface{}{"readyReplicas": int64(0)},
+	}}
+	if _, err := condition(watch.Event{Type: watch.Modified}, obj); err != nil {
+		t.Fatal(err)
+	}
+	if !evaluated {
+		t.Error("expected the condition to be evaluated")
+	}
+	if found := wp.status(); found != "ready 0/1, pods Running=1" {
+		t.Errorf("expected ready 0/1, pods Running=1, found: %q", found)
+	}
+}
+
+// TestWaitProgressListsPodsUnlocked tests the watch events are observed while the pods are being listed
+func TestWaitProgressListsPodsUnlocked(t *testing.T) {
+	listing := make(chan struct{})
+	release := make(chan struct{})
+	wp := &waitProgress{listPods: func(obj *unstructured.Unstructured) ([]corev1.Pod, error) {
+		close(listing)
+		<-release
+		return []corev1.Pod{{Status: corev1.PodStatus{Phase: corev1.PodPending}}}, nil
+	}}
+	condition := wp.observe(func(event watch.Event, obj *unstructured.Unstructured) (bool, error) {
+		return false, nil
+	})
+	obj := func(ready int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":   "Deployment",
+			"spec":   map[string]interface{}{"replicas": int64(1)},
+			"status": map[string]interface{}{"readyReplicas": ready},
+		}}
+	}
+	if _, err := condition(watch.Event{Type: watch.Added}, obj(0)); err != nil {
+		t.Fatal(err)
+	}
+	status := make(chan string)
+	go func() { status <- wp.status() }()
+	<-listing
+
+	observed := make(chan struct{})
+	go func() {
+		condition(watch.Event{Type: watch.Modified}, obj(1))
+		close(observed)
+	}()
+	select {
+	case <-observed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the event to be observed while the pods are listed")
+	}
+	close(release)
+	if found := <-status; found != "ready 1/1, pods Pending=1" {
+		t.Errorf("expected ready 1/1, pods Pending=1, found: %q", found)
+	}
+}
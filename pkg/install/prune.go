@@ -0,0 +1,113 @@
+package install
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"k8s.io/apimachinery/pkg/api/meta"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	// VersionLabel identifies objects installed by the forgeops-cli
+	VersionLabel = "forgeops-cli.forgerock.com/version"
+	// ComponentLabel identifies the component an object was installed with
+	ComponentLabel = "forgeops-cli.forgerock.com/component"
+)
+
+// defaultPruneResources resource types searched for objects that are no longer in the manifest.
+// PersistentVolumeClaims hold user data and are never pruned
+var defaultPruneResources = []string{
+	"configmaps",
+	"secrets",
+	"services",
+	"serviceaccounts",
+	"roles.rbac.authorization.k8s.io",
+	"rolebindings.rbac.authorization.k8s.io",
+	"deployments.apps",
+	"statefulsets.apps",
+	"jobs.batch",
+	"cronjobs.batch",
+	"ingresses.networking.k8s.io",
+	"networkpolicies.networking.k8s.io",
+	"poddisruptionbudgets.policy",
+}
+
+// prune deletes the objects of the applied components that are no longer part of the applied set.
+// Only namespaced objects in the namespaces of the applied objects are considered
+func prune(ctx context.Context, k8sCntMgr k8s.ClientMgr, applied []*resource.Info, opts Options) error {
+	var metadataAccessor = meta.NewAccessor()
+	appliedKeys := map[string]bool{}
+	components := map[string]bool{}
+	namespaces := map[string]bool{}
+	resourceTypes := append([]string{}, defaultPruneResources...)
+	seenTypes := map[string]bool{}
+	for _, t := range resourceTypes {
+		seenTypes[t] = true
+	}
+	for _, info := range applied {
+		appliedKeys[objectKey(info)] = true
+		labels, err := metadataAccessor.Labels(info.Object)
+		if err != nil {
+			return err
+		}
+		if c, ok := labels[ComponentLabel]; ok {
+			components[c] = true
+		}
+		if info.Mapping == nil || info.Mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			continue
+		}
+		namespaces[info.Namespace] = true
+		resourceType := info.Mapping.Resource.GroupResource().String()
+		if resourceType != "persistentvolumeclaims" && !seenTypes[resourceType] {
+			seenTypes[resourceType] = true
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+
+	errs := []error{}
+	for component := range components {
+		selector := fmt.Sprintf("%s=%s,%s", ComponentLabel, component, VersionLabel)
+		for ns := range namespaces {
+			for _, resourceType := range resourceTypes {
+				infos, err := k8sCntMgr.ListObjects(ctx, ns, resourceType, selector)
+				// The resource type isn't served by this cluster
+				if err != nil && meta.IsNoMatchError(err) {
+					continue
+				}
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				for _, info := range infos {
+					if appliedKeys[objectKey(info)] {
+						continue
+					}
+					kind := info.Mapping.GroupVersionKind.Kind
+					if opts.DryRun != DryRunNone {
+						printer.Noticef("%s %q pruned (%s dry run)", kind, info.Name, opts.DryRun)
+						continue
+					}
+					printer.Noticef("Pruning %s %q. It's no longer part of %q", kind, info.Name, component)
+					if err := k8sCntMgr.DeleteObject(ctx, info); err != nil {
+						errs = append(errs, err)
+					}
+				}
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// objectKey identifies an object by kind, namespace and name.
+// The group is left out as some kinds are served by several groups, e.g. Ingress
+func objectKey(info *resource.Info) string {
+	kind := info.Object.GetObjectKind().GroupVersionKind().Kind
+	if info.Mapping != nil {
+		kind = info.Mapping.GroupVersionKind.Kind
+	}
+	return fmt.Sprintf("%s/%s/%s", kind, info.Namespace, info.Name)
+}
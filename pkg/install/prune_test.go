@@ -0,0 +1,56 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/resource"
+
+	imock "github.com/ForgeRock/forgeops-cli/internal/mock"
+)
+
+// testDeployment is the manifest of the deployment %[1]s installed with the component %[2]s
+const testDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %[1]s
+  namespace: test_namespace
+  labels:
+    forgeops-cli.forgerock.com/version: 0.1.0
+    forgeops-cli.forgerock.com/component: %[2]s
+`
+
+// TestPrune tests only objects removed from the manifest are deleted
+func TestPrune(t *testing.T) {
+	am := newTestInfo(t, fmt.Sprintf(testDeployment, "am", "apps"), meta.RESTScopeNamespace)
+	removed := newTestInfo(t, fmt.Sprintf(testDeployment, "removed", "apps"), meta.RESTScopeNamespace)
+	selector := ComponentLabel + "=apps," + VersionLabel
+
+	testClientMgr := &imock.ClientMgr{}
+	testClientMgr.On("ListObjects", mock.Anything, "test_namespace", "deployments.apps", selector).
+		Return([]*resource.Info{newTestInfo(t, fmt.Sprintf(testDeployment, "am", "apps"), meta.RESTScopeNamespace), removed}, nil)
+	testClientMgr.On("ListObjects", mock.Anything, "test_namespace", mock.AnythingOfType("string"), selector).
+		Return([]*resource.Info{}, nil)
+	testClientMgr.On("DeleteObject", mock.Anything, removed).Return(nil)
+
+	if err := prune(context.Background(), testClientMgr, []*resource.Info{am}, Options{Prune: true}); err != nil {
+		t.Fatal(err)
+	}
+	testClientMgr.AssertNumberOfCalls(t, "DeleteObject", 1)
+	testClientMgr.AssertCalled(t, "DeleteObject", mock.Anything, removed)
+
+	// nothing is deleted during a dry run
+	dryRunClientMgr := &imock.ClientMgr{}
+	dryRunClientMgr.On("ListObjects", mock.Anything, "test_namespace", "deployments.apps", selector).
+		Return([]*resource.Info{removed}, nil)
+	dryRunClientMgr.On("ListObjects", mock.Anything, "test_namespace", mock.AnythingOfType("string"), selector).
+		Return([]*resource.Info{}, nil)
+	if err := prune(context.Background(), dryRunClientMgr, []*resource.Info{am}, Options{Prune: true, DryRun: DryRunServer}); err != nil {
+		t.Fatal(err)
+	}
+	dryRunClientMgr.AssertNotCalled(t, "DeleteObject", mock.Anything, removed)
+}
@@ -1,74 +1,84 @@
 package install
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
 	"github.com/ForgeRock/forgeops-cli/internal/k8s"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
-	"github.com/ForgeRock/forgeops-cli/internal/utils"
-	"github.com/ForgeRock/forgeops-cli/pkg/delete"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
 	"github.com/ForgeRock/forgeops-cli/pkg/doctor"
 	"github.com/ForgeRock/forgeops-cli/pkg/get"
 	"github.com/ForgeRock/forgeops-cli/pkg/health"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
-type qsSecret struct {
-	secretName string
-	keyName    []string
-	printName  []string
-}
-
-type qsConfig struct {
-	placeholderFQDN      string
-	placeholderNamespace string
-	importantSecrets     []qsSecret
+// ForgeRockComponent Installs the given component in the namespace provided.
+// "latest" is resolved to the tag of the latest release
+func ForgeRockComponent(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version, fqdn string, opts Options) error {
+	version, err := release.ResolveVersion(ctx, ghRepo, version)
+	if err != nil {
+		return err
+	}
+	warnMixedReleases(ctx, clientFactory, ghRepo, fileName, version)
+	return forgeRockComponent(ctx, clientFactory, ghRepo, fileName, version, fqdn, opts)
 }
 
-// ForgeRockComponent Installs the given component in the namespace provided
-func ForgeRockComponent(clientFactory factory.Factory, ghRepo, fileName, version, fqdn string) error {
-	fPath := fmt.Sprintf("https://github.com/%s/releases/latest/download/%s", ghRepo, fileName)
-	if len(version) == 0 {
-		version = "latest"
+// warnMixedReleases reports the components of the namespace installed from other releases of ghRepo
+func warnMixedReleases(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version string) {
+	records, err := inventory.List(ctx, clientFactory, false)
+	// The inventory is only used to warn, it's not required to install
+	if err != nil {
+		return
 	}
-	if version != "latest" {
-		fPath = fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", ghRepo, version, fileName)
+	others := []string{}
+	for _, r := range records {
+		if r.GHRepo == ghRepo && r.Component != inventory.ComponentName(fileName) && r.Version != version {
+			others = append(others, fmt.Sprintf("%s: %q", r.Component, r.Version))
+		}
 	}
-	config := qsConfig{
-		placeholderFQDN:      "default.iam.example.com",
-		placeholderNamespace: "default",
+	if len(others) > 0 {
+		printer.Warnf("Installing %q version: %q while other components were installed from other releases [%s]. Use --tag to install every component from the same release",
+			fileName, version, strings.Join(others, ", "))
 	}
+}
+
+func forgeRockComponent(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version, fqdn string, opts Options) error {
 	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
 	ns, err := k8sCntMgr.Namespace()
 	if err != nil {
 		return err
 	}
-	if len(fqdn) == 0 {
-		fqdn = fmt.Sprintf("%s.iam.example.com", ns)
-	}
 
 	if strings.Contains(fileName, "base") || strings.Contains(fileName, "quickstart") {
-		if err := checkDependencies(clientFactory, doctor.SecretAgentOperatorHealth); err != nil {
+		if err := checkDependencies(ctx, clientFactory, doctor.SecretAgentOperatorHealth, opts); err != nil {
 			return err
 		}
 	}
 	if strings.Contains(fileName, "ds") || strings.Contains(fileName, "quickstart") {
-		if err := checkDependencies(clientFactory, doctor.DSOperatorHealth); err != nil {
+		if err := checkDependencies(ctx, clientFactory, doctor.DSOperatorHealth, opts); err != nil {
 			return err
 		}
 	}
 	printer.NoticeHif("Targeting namespace: %q", ns)
 	printer.NoticeHif("Installing %q from %q version: %q ", fileName, ghRepo, version)
-	manifestStr, err := utils.DownloadTextFile(fPath)
+	rm, err := RenderForgeRockComponent(ctx, clientFactory, ghRepo, fileName, version, fqdn)
 	if err != nil {
 		return err
 	}
-
-	manifestStr = strings.ReplaceAll(manifestStr, config.placeholderFQDN, fqdn)
-	manifestStr = strings.ReplaceAll(manifestStr, "namespace: "+config.placeholderNamespace, "namespace: "+ns)
-	if err := ManifestStr(clientFactory, manifestStr, standardTransforms()...); err != nil {
+	if err := Resources(ctx, clientFactory, rm.Infos, opts); err != nil {
+		return err
+	}
+	if opts.DryRun != DryRunNone {
+		printer.Noticef("Rendered %q from %q version: %q (%s dry run)", fileName, ghRepo, version, opts.DryRun)
+		return nil
+	}
+	if err := recordInventory(ctx, clientFactory, ns, rm, opts); err != nil {
 		return err
 	}
 	printer.Noticef("Installed %q from %q version: %q ", fileName, ghRepo, version)
@@ -76,129 +86,237 @@ func ForgeRockComponent(clientFactory factory.Factory, ghRepo, fileName, version
 
 }
 
-// Quickstart Installs the quickstart in the namespace provided
-func Quickstart(clientFactory factory.Factory, ghRepo, version, fqdn string) error {
-
-	gvrDeployment := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
-	gvrJob := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
-	gvrStatefulsets := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
-
-	// TODO: We should obtain settings like these from a config that can be ingested at runtime.
-	// Storing these here for now until we have a solution
-	// https://github.com/ForgeRock/forgeops-cli/issues/58
-	config := qsConfig{
-		placeholderFQDN:      "default.iam.example.com",
-		placeholderNamespace: "default",
-		importantSecrets: []qsSecret{
-			{
-				secretName: "am-env-secrets",
-				keyName:    []string{"AM_PASSWORDS_AMADMIN_CLEAR"},
-				printName:  []string{"amadmin user"},
-			},
-			{
-				secretName: "ds-passwords",
-				keyName:    []string{"dirmanager.pw"},
-				printName:  []string{"uid=admin user"},
-			},
-			{
-				secretName: "rcs-agent-env-secrets",
-				keyName:    []string{"AGENT_IDM_SECRET", "AGENT_RCS_SECRET"},
-				printName:  []string{"rcs-agent IDM secret", "rcs-agent RCS secret"},
-			},
-		},
-	}
-	// BEGIN TIERED DEPLOYMENT
-	// DEPLOY BASE
-	if err := ForgeRockComponent(clientFactory, ghRepo, "base.yaml", version, fqdn); err != nil {
-		return err
+// RenderForgeRockComponent obtains the objects of the given component as they are applied by ForgeRockComponent
+func RenderForgeRockComponent(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version, fqdn string) (*ReleaseManifest, error) {
+	version, err := release.ResolveVersion(ctx, ghRepo, version)
+	if err != nil {
+		return nil, err
 	}
-	printer.Noticef("Waiting for secrets to be generated")
-	if err := waitForSecrets(clientFactory, config.importantSecrets); err != nil {
-		return err
+	placeholders := profile.Current().Spec.Placeholders
+	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
+	ns, err := k8sCntMgr.Namespace()
+	if err != nil {
+		return nil, err
 	}
-	printer.Noticef("Waiting for git-server to become available")
-	if _, err := waitForCondition(clientFactory, gvrDeployment, "git-server", "status.availableReplicas>=1", 120); err != nil {
-		return err
+	if len(fqdn) == 0 {
+		fqdn = fmt.Sprintf("%s.iam.example.com", ns)
 	}
-	// DEPLOY DS
-	if err := ForgeRockComponent(clientFactory, ghRepo, "ds.yaml", version, fqdn); err != nil {
-		return err
+	location := release.Location(ghRepo, version, fileName)
+	printer.Noticef("Reading %q", location)
+	manifestStr, err := release.Fetch(ctx, ghRepo, version, fileName)
+	if err != nil {
+		return nil, err
 	}
-	printer.Noticef("Waiting for DS deployment to become available. This can take several minutes")
-	if _, err := waitForCondition(clientFactory, gvrStatefulsets, "ds-idrepo",
-		"status.readyReplicas==spec.replicas", 600); err != nil {
-		return err
+	rm := newReleaseManifest(ghRepo, fileName, version, manifestStr)
+	rm.FQDN = fqdn
+
+	transforms := append(standardTransforms(inventory.ComponentName(fileName)),
+		FQDNTransform(placeholders.FQDN, fqdn),
+		ProvenanceTransform(location, version, rm.Checksum))
+	if rm.Infos, err = Render(clientFactory, manifestStr, transforms...); err != nil {
+		return nil, err
 	}
-	// DEPLOY APPS
-	if err := ForgeRockComponent(clientFactory, ghRepo, "apps.yaml", version, fqdn); err != nil {
-		return err
+	if err := setNamespaces(rm.Infos, placeholders.Namespace, ns); err != nil {
+		return nil, err
 	}
-	printer.Noticef("Waiting for AM deployment to become available. This can take several minutes")
-	if _, err := waitForCondition(clientFactory, gvrDeployment, "am", "status.availableReplicas>=1", 600); err != nil {
+	return rm, nil
+}
+
+// Quickstart Installs the quickstart in the namespace provided tier by tier, waiting for each tier as described in the profile.
+// "latest" is resolved once so every component is installed from the same release.
+// Completed steps are checkpointed in the namespace. With opts.Resume the steps completed before are skipped
+func Quickstart(ctx context.Context, clientFactory factory.Factory, ghRepo, version, fqdn string, opts Options) error {
+	p := profile.Current()
+	opts = opts.startTimeout(time.Now())
+	checkpoint := inventory.Checkpoint{}
+	if opts.Resume {
+		previous, err := inventory.GetCheckpoint(ctx, clientFactory)
+		switch {
+		case apierrors.IsNotFound(err):
+			printer.Warnf("There's no quickstart to resume in this namespace. Installing from the first step")
+		case err != nil:
+			return err
+		default:
+			if version, fqdn, err = resumeCheckpoint(previous, ghRepo, version, fqdn); err != nil {
+				return err
+			}
+			printer.NoticeHif("Resuming the quickstart of %q version: %q. %d steps completed before", ghRepo, previous.Version, len(previous.Completed))
+			checkpoint = previous
+		}
+	}
+	version, err := release.ResolveVersion(ctx, ghRepo, version)
+	if err != nil {
 		return err
 	}
-	printer.Noticef("Waiting for amster job to complete. This can take several minutess")
-	if _, err := waitForCondition(clientFactory, gvrJob, "amster", "status.succeeded>=1", 300); err != nil {
+	checkpoint.GHRepo, checkpoint.Version, checkpoint.FQDN = ghRepo, version, fqdn
+
+	steps, err := quickstartSteps(ctx, clientFactory, p, ghRepo, version, fqdn, opts)
+	if err != nil {
 		return err
 	}
-	// DELETE AMSTER
-	if err := delete.ForgeRockComponent(clientFactory, ghRepo, "amster.yaml", version, true); err != nil {
+	save := func(c inventory.Checkpoint) error {
+		return inventory.WriteCheckpoint(ctx, clientFactory, c)
+	}
+	// Nothing is persisted in dry runs
+	if opts.DryRun != DryRunNone {
+		save = nil
+	}
+	if err := runSteps(ctx, steps, &checkpoint, save); err != nil {
 		return err
 	}
-	// DEPLOY UI
-	if err := ForgeRockComponent(clientFactory, ghRepo, "ui.yaml", version, fqdn); err != nil {
+	if opts.DryRun != DryRunNone {
+		printer.Noticef("CDQ dry run complete. No changes were made")
+		return nil
+	}
+	// There's nothing left to resume
+	if err := inventory.DeleteCheckpoint(ctx, clientFactory); err != nil {
 		return err
 	}
-	// END TIERED DEPLOYMENT
 
-	if err := get.Secrets(clientFactory); err != nil {
+	if err := get.Secrets(ctx, clientFactory); err != nil {
 		return err
 	}
-	if err := get.URLs(clientFactory, "forgerock"); err != nil {
+	if err := get.URLs(ctx, clientFactory, "forgerock"); err != nil {
 		return err
 	}
 	printer.Noticef("CDQ Deployment Complete. Enjoy!")
 	return nil
 }
 
-func checkDependencies(clientFactory factory.Factory, hlthCheck []byte) error {
-	hlth, err := health.GetHealthFromBytes(hlthCheck)
-	if err != nil {
-		return err
+// step a step of the quickstart. The ids of the completed steps are checkpointed
+type step struct {
+	id   string
+	tier string
+	// state left behind when the step doesn't complete
+	state string
+	run   func() error
+}
+
+// quickstartSteps lists the steps installing the tiers of the profile: the components of each tier,
+// the waits of the tier and the cleanup of its components. Nothing is persisted in dry runs, there's nothing to wait for
+func quickstartSteps(ctx context.Context, clientFactory factory.Factory, p *profile.Profile, ghRepo, version, fqdn string, opts Options) ([]step, error) {
+	steps := []step{}
+	for _, tier := range p.Spec.Tiers {
+		for _, componentName := range tier.Components {
+			component, err := p.Component(componentName)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step{
+				id:    fmt.Sprintf("%s/install/%s", tier.Name, componentName),
+				tier:  tier.Name,
+				state: fmt.Sprintf("%q may be partially applied. Resuming applies it again", component.Manifest),
+				run: func() error {
+					return forgeRockComponent(ctx, clientFactory, ghRepo, component.Manifest, version, fqdn, opts)
+				},
+			})
+		}
+		if opts.DryRun != DryRunNone {
+			continue
+		}
+		for _, w := range tier.Waits {
+			w := w
+			steps = append(steps, step{
+				id:    fmt.Sprintf("%s/wait/%s/%s", tier.Name, w.Resource, w.Name),
+				tier:  tier.Name,
+				state: fmt.Sprintf("The %q tier is applied but %s %q isn't ready yet. Resuming waits for it again", tier.Name, w.Resource, w.Name),
+				run:   func() error { return waitFor(ctx, clientFactory, w, opts) },
+			})
+		}
+		for _, componentName := range tier.Cleanup {
+			componentName := componentName
+			steps = append(steps, step{
+				id:    fmt.Sprintf("%s/cleanup/%s", tier.Name, componentName),
+				tier:  tier.Name,
+				state: fmt.Sprintf("%q may be partially deleted. Resuming deletes it again", componentName),
+				run: func() error {
+					return cleanupComponent(ctx, clientFactory, p, componentName, ghRepo, version)
+				},
+			})
+		}
 	}
-	operAllHealthy, err := health.Run(clientFactory, hlth, true)
-	if !operAllHealthy {
-		return health.ErrNotAllHealthy
+	return steps, nil
+}
+
+// runSteps runs the steps in order and saves the checkpoint after each step when save is set.
+// The steps completed in the checkpoint provided are skipped until the first step that didn't complete.
+// The step running when ctx is cancelled is reported with the state it leaves behind
+func runSteps(ctx context.Context, steps []step, checkpoint *inventory.Checkpoint, save func(inventory.Checkpoint) error) error {
+	previous := *checkpoint
+	checkpoint.Completed = []string{}
+	resuming := true
+	for _, s := range steps {
+		if resuming && previous.Done(s.id) {
+			printer.Noticef("Skipping %q. It completed before", s.id)
+			checkpoint.Completed = append(checkpoint.Completed, s.id)
+			continue
+		}
+		resuming = false
+		if err := s.run(); err != nil {
+			if ctx.Err() != nil {
+				reportInterrupted(s, checkpoint, len(steps), save != nil)
+				return fmt.Errorf("quickstart interrupted during step %q: %w", s.id, ctx.Err())
+			}
+			if save == nil {
+				return err
+			}
+			return fmt.Errorf("quickstart step %q didn't complete: %w. Run \"forgeops install quickstart --resume\" to continue from this step", s.id, err)
+		}
+		checkpoint.Completed = append(checkpoint.Completed, s.id)
+		if save == nil {
+			continue
+		}
+		if err := save(*checkpoint); err != nil {
+			return fmt.Errorf("error checkpointing quickstart step %q: %w", s.id, err)
+		}
 	}
-	return err
+	return nil
 }
 
-func waitForSecrets(clientFactory factory.Factory, importantSecrets []qsSecret) error {
-	// kubectl api-resources -o wide
-	gvr := schema.GroupVersionResource{
-		Group:    "",
-		Version:  "v1",
-		Resource: "secrets",
+// reportInterrupted prints the interrupted step, the state it left behind and the steps completed
+func reportInterrupted(s step, checkpoint *inventory.Checkpoint, total int, resumable bool) {
+	printer.Warnf("Interrupted step %q of the %q tier", s.id, s.tier)
+	printer.Warnln(s.state)
+	last := "none"
+	if n := len(checkpoint.Completed); n > 0 {
+		last = checkpoint.Completed[n-1]
 	}
-	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
-	ns, err := k8sCntMgr.Namespace()
-	if err != nil {
-		return err
+	printer.Warnf("%d of %d steps completed. Last completed step: %s", len(checkpoint.Completed), total, last)
+	if resumable {
+		printer.Warnln("Run \"forgeops install quickstart --resume\" to continue from the interrupted step")
 	}
-	for _, secret := range importantSecrets {
-		if _, err := k8sCntMgr.WaitForResource(30, ns, secret.secretName, gvr); err != nil {
-			return err
-		}
+}
+
+// resumeCheckpoint returns the version and FQDN of the quickstart being resumed.
+// The version and FQDN requested must be the ones of the checkpoint when they're set
+func resumeCheckpoint(checkpoint inventory.Checkpoint, ghRepo, version, fqdn string) (string, string, error) {
+	if len(checkpoint.GHRepo) > 0 && checkpoint.GHRepo != ghRepo {
+		return "", "", fmt.Errorf("the quickstart being resumed installs %q, not %q", checkpoint.GHRepo, ghRepo)
 	}
-	return nil
+	if len(version) > 0 && version != "latest" && version != checkpoint.Version {
+		return "", "", fmt.Errorf("the quickstart being resumed installs version %q. Use --tag %s or install %q without --resume",
+			checkpoint.Version, checkpoint.Version, version)
+	}
+	if len(fqdn) > 0 && fqdn != checkpoint.FQDN {
+		return "", "", fmt.Errorf("the quickstart being resumed uses the FQDN %q. Use --fqdn %s or install without --resume",
+			checkpoint.FQDN, checkpoint.FQDN)
+	}
+	return checkpoint.Version, checkpoint.FQDN, nil
 }
 
-func waitForCondition(clientFactory factory.Factory, gvr schema.GroupVersionResource, name, expr string, timeout int) (bool, error) {
-	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
-	ns, err := k8sCntMgr.Namespace()
+func checkDependencies(ctx context.Context, clientFactory factory.Factory, hlthCheck []byte, opts Options) error {
+	hlth, err := health.GetHealthFromBytes(hlthCheck)
 	if err != nil {
-		return false, err
+		return err
 	}
-	met, err := k8sCntMgr.WatchEventsForCondition(timeout, ns, name, gvr, k8s.ConditionExpression(expr))
-	return met, nil
+	operAllHealthy, err := health.Run(ctx, clientFactory, hlth, true)
+	if !operAllHealthy {
+		err = health.ErrNotAllHealthy
+	}
+	// Missing dependencies don't prevent reviewing what would be installed
+	if err != nil && opts.DryRun != DryRunNone {
+		printer.Warnf("Ignoring failed dependency check during dry run: %s", err)
+		return nil
+	}
+	return err
 }
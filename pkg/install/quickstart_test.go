@@ -0,0 +1,159 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+)
+
+// TestQuickstartSteps tests the steps follow the tiers of the profile
+func TestQuickstartSteps(t *testing.T) {
+	p := profile.Current()
+	steps, err := quickstartSteps(context.Background(), nil, p, "ForgeRock/forgeops", "v1", "", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, s := range steps {
+		ids = append(ids, s.id)
+	}
+	expected := []string{
+		"base/install/base",
+		"base/wait/secrets/am-env-secrets",
+		"base/wait/secrets/ds-passwords",
+		"base/wait/secrets/rcs-agent-env-secrets",
+		"base/wait/deployments/git-server",
+		"directory/install/directory",
+		"directory/wait/statefulsets/ds-idrepo",
+		"apps/install/apps",
+		"apps/wait/deployments/am",
+		"apps/wait/jobs/amster",
+		"apps/cleanup/amster",
+		"ui/install/ui",
+	}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected steps %v, found: %v", expected, ids)
+	}
+
+	steps, err = quickstartSteps(context.Background(), nil, p, "ForgeRock/forgeops", "v1", "", Options{DryRun: DryRunClient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 4 {
+		t.Errorf("expected only the install steps in dry runs, found: %d steps", len(steps))
+	}
+}
+
+// TestRunSteps tests completed steps are checkpointed and skipped when resuming
+func TestRunSteps(t *testing.T) {
+	ran := []string{}
+	fail := map[string]bool{}
+	newSteps := func(ids ...string) []step {
+		steps := []step{}
+		for _, id := range ids {
+			id := id
+			steps = append(steps, step{id: id, run: func() error {
+				ran = append(ran, id)
+				if fail[id] {
+					return errors.New("timed out")
+				}
+				return nil
+			}})
+		}
+		return steps
+	}
+	saved := []inventory.Checkpoint{}
+	save := func(c inventory.Checkpoint) error {
+		saved = append(saved, c)
+		return nil
+	}
+
+	// the first run stops at the failed step
+	fail["b"] = true
+	checkpoint := inventory.Checkpoint{Version: "v1"}
+	if err := runSteps(context.Background(), newSteps("a", "b", "c"), &checkpoint, save); err == nil {
+		t.Fatal("expected the failed step to be reported")
+	}
+	if !reflect.DeepEqual(ran, []string{"a", "b"}) {
+		t.Errorf("expected steps a and b to run, found: %v", ran)
+	}
+	if len(saved) != 1 || !reflect.DeepEqual(saved[0].Completed, []string{"a"}) {
+		t.Fatalf("expected step a to be checkpointed, found: %+v", saved)
+	}
+
+	// resuming continues from the failed step
+	ran = []string{}
+	fail["b"] = false
+	checkpoint = saved[0]
+	if err := runSteps(context.Background(), newSteps("a", "b", "c"), &checkpoint, save); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ran, []string{"b", "c"}) {
+		t.Errorf("expected steps b and c to run, found: %v", ran)
+	}
+	if !reflect.DeepEqual(checkpoint.Completed, []string{"a", "b", "c"}) {
+		t.Errorf("expected every step to be completed, found: %v", checkpoint.Completed)
+	}
+
+	// steps recorded after the first incomplete step run again
+	ran = []string{}
+	checkpoint = inventory.Checkpoint{Completed: []string{"a", "c"}}
+	if err := runSteps(context.Background(), newSteps("a", "b", "c"), &checkpoint, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ran, []string{"b", "c"}) {
+		t.Errorf("expected steps b and c to run, found: %v", ran)
+	}
+
+	// interrupted steps stop the run and report the cancellation
+	ran = []string{}
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := newSteps("a", "b", "c")
+	steps[1].run = func() error {
+		ran = append(ran, "b")
+		cancel()
+		return errors.New("watch closed")
+	}
+	checkpoint = inventory.Checkpoint{}
+	err := runSteps(ctx, steps, &checkpoint, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the interruption to be reported, found: %+v", err)
+	}
+	if !reflect.DeepEqual(ran, []string{"a", "b"}) {
+		t.Errorf("expected steps a and b to run, found: %v", ran)
+	}
+	if !reflect.DeepEqual(checkpoint.Completed, []string{"a"}) {
+		t.Errorf("expected only step a to be completed, found: %v", checkpoint.Completed)
+	}
+}
+
+// TestResumeCheckpoint tests the version and FQDN requested must match the quickstart being resumed
+func TestResumeCheckpoint(t *testing.T) {
+	checkpoint := inventory.Checkpoint{GHRepo: "ForgeRock/forgeops", Version: "v1", FQDN: "demo.example.com"}
+	td := []struct {
+		// comment about test case
+		testComment string
+		version     string
+		fqdn        string
+		expectErr   bool
+	}{
+		{testComment: "latest resumes the checkpoint version", version: "latest", expectErr: false},
+		{testComment: "the checkpoint version is accepted", version: "v1", fqdn: "demo.example.com", expectErr: false},
+		{testComment: "other versions are rejected", version: "v2", expectErr: true},
+		{testComment: "other FQDNs are rejected", version: "latest", fqdn: "other.example.com", expectErr: true},
+	}
+	for _, tc := range td {
+		version, fqdn, err := resumeCheckpoint(checkpoint, "ForgeRock/forgeops", tc.version, tc.fqdn)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+			continue
+		}
+		if err == nil && (version != "v1" || fqdn != "demo.example.com") {
+			t.Errorf("%s expected v1 and demo.example.com, found: %q and %q", tc.testComment, version, fqdn)
+		}
+	}
+}
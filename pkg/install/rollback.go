@@ -0,0 +1,166 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Rollback reinstalls a previous release of the given component recorded in the inventory.
+// An empty version selects the release installed before the current one.
+// Objects added by the newer release are pruned. The components of the CDQ tiers are rolled back like an upgrade:
+// the amster job isn't run again and the component must pass the health checks of its tier within the timeout
+func Rollback(ctx context.Context, clientFactory factory.Factory, component, version string, timeout time.Duration, opts Options) error {
+	opts = opts.startTimeout(time.Now())
+	p := profile.Current()
+	// The components of the profile are recorded under their name, they can be given by alias
+	if c, err := p.Component(component); err == nil {
+		component = c.Name
+	}
+	record, err := inventory.Get(ctx, clientFactory, component)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%q wasn't installed in this namespace by the forgeops-cli. There's nothing to roll back", component)
+	}
+	if err != nil {
+		return err
+	}
+	if len(version) == 0 {
+		if version, err = inventory.PreviousVersion(record); err != nil {
+			return err
+		}
+	}
+	opts.Prune = true
+	opts.rollback = true
+	printer.NoticeHif("Rolling back %q from version %q to %q", component, record.Version, version)
+
+	// The operators are installed as published. The ForgeRock manifests are rendered for the namespace and FQDN
+	if record.GHRepo != release.ForgeOpsRepo {
+		return GHResource(ctx, clientFactory, record.GHRepo, record.Manifest, version, opts)
+	}
+	tiers := selectTiers(p, component)
+	// Components outside of the tiers don't have health checks
+	if len(tiers) == 0 {
+		return ForgeRockComponent(ctx, clientFactory, record.GHRepo, record.Manifest, version, record.FQDN, opts)
+	}
+	if version, err = release.ResolveVersion(ctx, record.GHRepo, version); err != nil {
+		return err
+	}
+	if err := upgradeTiers(ctx, clientFactory, p, tiers, record.GHRepo, version, record.FQDN, timeout, opts); err != nil {
+		return err
+	}
+	if opts.DryRun != DryRunNone {
+		printer.Noticef("Rollback dry run of %q complete. No changes were made", component)
+		return nil
+	}
+	printer.Noticef("%q rolled back to version %q", component, version)
+	return nil
+}
+
+// RollbackQuickstart reinstalls a previous release of the CDQ tier by tier like an upgrade.
+// An empty version selects the release installed before the current one, which must be the same for every component.
+// Objects added by the newer release are pruned
+func RollbackQuickstart(ctx context.Context, clientFactory factory.Factory, version string, timeout time.Duration, opts Options) error {
+	opts = opts.startTimeout(time.Now())
+	p := profile.Current()
+	records, err := tierRecords(ctx, clientFactory, p)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("the CDQ wasn't installed in this namespace by the forgeops-cli. There's nothing to roll back")
+	}
+	current := map[string]bool{}
+	for _, record := range records {
+		current[record.Version] = true
+	}
+	if version, err = rollbackVersion(records, version); err != nil {
+		return err
+	}
+	ghRepo := records[0].GHRepo
+	if version, err = release.ResolveVersion(ctx, ghRepo, version); err != nil {
+		return err
+	}
+	opts.Prune = true
+	opts.rollback = true
+	printer.NoticeHif("Rolling back the CDQ from version %q to %q", strings.Join(keys(current), ", "), version)
+	if err := upgradeTiers(ctx, clientFactory, p, p.Spec.Tiers, ghRepo, version, records[0].FQDN, timeout, opts); err != nil {
+		return err
+	}
+	if opts.DryRun != DryRunNone {
+		printer.Noticef("CDQ rollback dry run complete. No changes were made")
+		return nil
+	}
+	printer.Noticef("CDQ rolled back to version %q", version)
+	return nil
+}
+
+// rollbackVersion returns the version the CDQ components recorded are rolled back to.
+// The version given is used as is. Otherwise it's the version installed before the current one, the same for every component
+func rollbackVersion(records []inventory.Record, version string) (string, error) {
+	if len(version) > 0 {
+		return version, nil
+	}
+	previous := map[string]bool{}
+	for _, record := range records {
+		v, err := inventory.PreviousVersion(record)
+		if err != nil {
+			return "", err
+		}
+		previous[v] = true
+	}
+	if len(previous) != 1 {
+		return "", fmt.Errorf("the CDQ components were previously installed from different versions (%s). Use --tag to select the version", strings.Join(keys(previous), ", "))
+	}
+	return keys(previous)[0], nil
+}
+
+// selectTiers returns the tiers of the profile the given component is part of, limited to that component
+func selectTiers(p *profile.Profile, component string) []profile.Tier {
+	selected := []profile.Tier{}
+	for _, tier := range p.Spec.Tiers {
+		for _, c := range tier.Components {
+			if c == component {
+				selected = append(selected, profile.Tier{Name: tier.Name, Components: []string{component}})
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// tierRecords returns the inventory records of the tier components installed in the namespace
+func tierRecords(ctx context.Context, clientFactory factory.Factory, p *profile.Profile) ([]inventory.Record, error) {
+	records := []inventory.Record{}
+	for _, tier := range p.Spec.Tiers {
+		for _, component := range tier.Components {
+			record, err := inventory.Get(ctx, clientFactory, component)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func keys(m map[string]bool) []string {
+	k := make([]string, 0, len(m))
+	for key := range m {
+		k = append(k, key)
+	}
+	sort.Strings(k)
+	return k
+}
@@ -0,0 +1,217 @@
+package install
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	imock "github.com/ForgeRock/forgeops-cli/internal/mock"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// newInventoryFactory returns a factory whose cluster stores the given inventory records in the "prod" namespace.
+// Nothing else is served, the manifests can't be rendered or applied
+func newInventoryFactory(t *testing.T, records ...inventory.Record) *imock.Factory {
+	configMaps := map[string]corev1.ConfigMap{}
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := "forgeops-inventory-" + record.Component
+		configMaps["/api/v1/namespaces/prod/configmaps/"+name] = corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "prod"},
+			Data:       map[string]string{"record": string(data)},
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		cm, ok := configMaps[r.URL.Path]
+		if r.Method != http.MethodGet || !ok {
+			status := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, r.URL.Path).Status()
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(&status)
+			return
+		}
+		json.NewEncoder(w).Encode(&cm)
+	}))
+	t.Cleanup(server.Close)
+
+	ns := "prod"
+	flags := genericclioptions.NewConfigFlags(false)
+	flags.Namespace = &ns
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &imock.Factory{}
+	f.On("GetOverrideFlags").Return(flags, nil)
+	f.On("StaticClient").Return(clientset, nil)
+	return f
+}
+
+// TestRollback tests what a component can be rolled back to is checked before anything is applied
+func TestRollback(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		component   string
+		records     []inventory.Record
+		expected    string
+	}{
+		{
+			testComment: "components not in the inventory have nothing to roll back",
+			component:   "apps",
+			expected:    `"apps" wasn't installed in this namespace`,
+		},
+		{
+			testComment: "components are looked up by alias",
+			component:   "ds",
+			records:     []inventory.Record{{Component: "directory", Version: "v1"}},
+			expected:    `no version of "directory" was installed before "v1"`,
+		},
+		{
+			testComment: "a second rollback doesn't return to the version rolled back from",
+			component:   "apps",
+			records: []inventory.Record{{Component: "apps", Version: "v1", RolledBack: true,
+				History: []inventory.Record{{Version: "v2"}, {Version: "v1"}}}},
+			expected: `no version of "apps" was installed before "v1"`,
+		},
+	}
+	for _, tc := range td {
+		f := newInventoryFactory(t, tc.records...)
+		err := Rollback(context.Background(), f, tc.component, "", time.Minute, Options{})
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s expected: %q, found: %+v", tc.testComment, tc.expected, err)
+		}
+		// Builder isn't mocked. Nothing was rendered
+		f.AssertNotCalled(t, "Builder")
+	}
+}
+
+// TestRollbackQuickstart tests the CDQ is only rolled back to a version every tier component was installed from
+func TestRollbackQuickstart(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		records     []inventory.Record
+		expected    string
+	}{
+		{
+			testComment: "a CDQ not in the inventory has nothing to roll back",
+			expected:    "the CDQ wasn't installed in this namespace",
+		},
+		{
+			testComment: "the components must have been installed from the same version before",
+			records: []inventory.Record{
+				{Component: "base", Version: "v3", History: []inventory.Record{{Version: "v2"}}},
+				{Component: "apps", Version: "v3", History: []inventory.Record{{Version: "v1"}}},
+			},
+			expected: "previously installed from different versions (v1, v2)",
+		},
+	}
+	for _, tc := range td {
+		f := newInventoryFactory(t, tc.records...)
+		err := RollbackQuickstart(context.Background(), f, "", time.Minute, Options{})
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s expected: %q, found: %+v", tc.testComment, tc.expected, err)
+		}
+		f.AssertNotCalled(t, "Builder")
+	}
+}
+
+// TestRollbackVersion tests the version the CDQ is rolled back to
+func TestRollbackVersion(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		records     []inventory.Record
+		version     string
+		expected    string
+		expectErr   bool
+	}{
+		{
+			testComment: "the version given is used as is",
+			records:     []inventory.Record{{Component: "base", Version: "v2"}},
+			version:     "v1",
+			expected:    "v1",
+		},
+		{
+			testComment: "the version installed before by every component",
+			records: []inventory.Record{
+				{Component: "base", Version: "v2", History: []inventory.Record{{Version: "v1"}}},
+				{Component: "apps", Version: "v2", History: []inventory.Record{{Version: "v2"}, {Version: "v1"}}},
+			},
+			expected: "v1",
+		},
+		{
+			testComment: "the previous versions are replayed past rollbacks",
+			records: []inventory.Record{
+				{Component: "base", Version: "v2", RolledBack: true, History: []inventory.Record{{Version: "v3"}, {Version: "v2"}, {Version: "v1"}}},
+				{Component: "apps", Version: "v2", RolledBack: true, History: []inventory.Record{{Version: "v3"}, {Version: "v2"}, {Version: "v1"}}},
+			},
+			expected: "v1",
+		},
+		{
+			testComment: "components without a previous version",
+			records:     []inventory.Record{{Component: "base", Version: "v1"}},
+			expectErr:   true,
+		},
+		{
+			testComment: "components previously installed from different versions",
+			records: []inventory.Record{
+				{Component: "base", Version: "v3", History: []inventory.Record{{Version: "v2"}}},
+				{Component: "apps", Version: "v3", History: []inventory.Record{{Version: "v1"}}},
+			},
+			expectErr: true,
+		},
+	}
+	for _, tc := range td {
+		version, err := rollbackVersion(tc.records, tc.version)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+		}
+		if version != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, version)
+		}
+	}
+}
+
+// TestSelectTiers tests a component is rolled back on its own, with the health checks of its tier only
+func TestSelectTiers(t *testing.T) {
+	p := profile.Current()
+	tiers := selectTiers(p, "apps")
+	expected := []profile.Tier{{Name: "apps", Components: []string{"apps"}}}
+	if !reflect.DeepEqual(tiers, expected) {
+		t.Errorf("expected: %+v, found: %+v", expected, tiers)
+	}
+	if tiers := selectTiers(p, "am"); len(tiers) != 0 {
+		t.Errorf("expected components outside of the tiers not to be selected, found: %+v", tiers)
+	}
+}
+
+// TestInventoryRecord tests the components installed by a rollback are recorded as such
+func TestInventoryRecord(t *testing.T) {
+	rm := newReleaseManifest("ForgeRock/forgeops", "apps.yaml", "v1", "")
+	if record := inventoryRecord("prod", rm, Options{}, metav1.Now()); record.RolledBack {
+		t.Errorf("expected installs not to be recorded as rollbacks")
+	}
+	record := inventoryRecord("prod", rm, Options{rollback: true}, metav1.Now())
+	if !record.RolledBack || record.Component != "apps" || record.Version != "v1" {
+		t.Errorf("expected the rollback of apps to v1 to be recorded, found: %+v", record)
+	}
+}
@@ -0,0 +1,227 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/pkg/sizing"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+)
+
+// SizeTransform sets the replicas, container resources and storage of the workloads listed in the size.
+// Deployments, StatefulSets and DirectoryServices are sized
+func SizeTransform(size sizing.Size) TransformInfoFunc {
+	return func(info *resource.Info) error {
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		kind := obj.GetKind()
+		if kind != "Deployment" && kind != "StatefulSet" && kind != "DirectoryService" {
+			return nil
+		}
+		w, found := size.Workload(obj.GetName())
+		if !found {
+			return nil
+		}
+		changed := []string{}
+		if w.Replicas != nil {
+			if err := unstructured.SetNestedField(obj.Object, int64(*w.Replicas), "spec", "replicas"); err != nil {
+				return err
+			}
+			changed = append(changed, "spec.replicas")
+		}
+		if w.Resources != nil {
+			resources, err := runtime.DefaultUnstructuredConverter.ToUnstructured(w.Resources)
+			if err != nil {
+				return err
+			}
+			if kind == "DirectoryService" {
+				if err := setResources(obj.Object, resources, "spec", "resources"); err != nil {
+					return err
+				}
+				changed = append(changed, "spec.resources")
+			} else {
+				fields := append(append([]string{}, podSpecPaths[kind]...), "containers")
+				containers, _, err := unstructured.NestedSlice(obj.Object, fields...)
+				if err != nil {
+					return err
+				}
+				for i, c := range containers {
+					container, ok := c.(map[string]interface{})
+					if !ok || (len(w.Container) > 0 && container["name"] != w.Container) {
+						continue
+					}
+					if err := setResources(container, resources, "resources"); err != nil {
+						return err
+					}
+					changed = append(changed, fmt.Sprintf("%s[%d].resources", strings.Join(fields, "."), i))
+				}
+				if err := unstructured.SetNestedSlice(obj.Object, containers, fields...); err != nil {
+					return err
+				}
+			}
+		}
+		if w.Storage != nil {
+			switch kind {
+			case "DirectoryService":
+				if err := unstructured.SetNestedField(obj.Object, w.Storage.String(), "spec", "storage"); err != nil {
+					return err
+				}
+				changed = append(changed, "spec.storage")
+			case "StatefulSet":
+				templates, _, err := unstructured.NestedSlice(obj.Object, "spec", "volumeClaimTemplates")
+				if err != nil {
+					return err
+				}
+				found := false
+				for i, t := range templates {
+					template, ok := t.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					if name, _, _ := unstructured.NestedString(template, "metadata", "name"); name != w.Claim() {
+						continue
+					}
+					if err := unstructured.SetNestedField(template, w.Storage.String(), "spec", "resources", "requests", "storage"); err != nil {
+						return err
+					}
+					changed = append(changed, fmt.Sprintf("spec.volumeClaimTemplates[%d].spec.resources.requests.storage", i))
+					found = true
+				}
+				if !found {
+					printer.Warnf("%s %q has no volume claim template %q. The storage of size %q isn't set", kind, info.Name, w.Claim(), size.Name)
+					break
+				}
+				if err := unstructured.SetNestedSlice(obj.Object, templates, "spec", "volumeClaimTemplates"); err != nil {
+					return err
+				}
+			}
+		}
+		if len(changed) > 0 {
+			printer.Noticef("%s %q: set %s for size %q", kind, info.Name, strings.Join(changed, ", "), size.Name)
+		}
+		return nil
+	}
+}
+
+// claimStorage storage requested by the volume claim templates of the StatefulSets in the cluster.
+// The storage is indexed by StatefulSet, see objectKey, and by claim
+type claimStorage map[string]map[string]string
+
+// liveClaimStorage returns the storage of the volume claim templates of the StatefulSets in infos that exist in the cluster
+func liveClaimStorage(ctx context.Context, clientFactory factory.Factory, infos []*resource.Info) (claimStorage, error) {
+	live := claimStorage{}
+	var dclient dynamic.Interface
+	for _, info := range infos {
+		if info.Mapping == nil || info.Mapping.GroupVersionKind.Kind != "StatefulSet" {
+			continue
+		}
+		if dclient == nil {
+			var err error
+			if dclient, err = clientFactory.DynamicClient(); err != nil {
+				return nil, err
+			}
+		}
+		obj, err := dclient.Resource(info.Mapping.Resource).Namespace(info.Namespace).Get(ctx, info.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		live[objectKey(info)] = volumeClaimStorage(obj)
+	}
+	return live, nil
+}
+
+// volumeClaimStorage returns the storage requested by the volume claim templates of the StatefulSet by claim
+func volumeClaimStorage(obj *unstructured.Unstructured) map[string]string {
+	storage := map[string]string{}
+	templates, _, _ := unstructured.NestedSlice(obj.Object, "spec", "volumeClaimTemplates")
+	for _, t := range templates {
+		template, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(template, "metadata", "name")
+		storage[name], _, _ = unstructured.NestedString(template, "spec", "resources", "requests", "storage")
+	}
+	return storage
+}
+
+// keepClaimStorage keeps the storage of the volume claim templates of the StatefulSets in the cluster.
+// The volume claim templates can't be updated, a different storage is reported and left out of the apply
+func keepClaimStorage(live claimStorage) TransformInfoFunc {
+	return func(info *resource.Info) error {
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok || obj.GetKind() != "StatefulSet" {
+			return nil
+		}
+		current, found := live[objectKey(info)]
+		if !found {
+			return nil
+		}
+		templates, _, err := unstructured.NestedSlice(obj.Object, "spec", "volumeClaimTemplates")
+		if err != nil {
+			return err
+		}
+		kept := false
+		for _, t := range templates {
+			template, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(template, "metadata", "name")
+			storage, _, _ := unstructured.NestedString(template, "spec", "resources", "requests", "storage")
+			liveStorage, found := current[name]
+			if !found || len(liveStorage) == 0 || storage == liveStorage {
+				continue
+			}
+			printer.Warnf("StatefulSet %q: the storage of volume claim %q is kept at %s instead of %s. "+
+				"Volume claim templates can't be changed, resize the persistent volume claims instead", info.Name, name, liveStorage, storage)
+			if err := unstructured.SetNestedField(template, liveStorage, "spec", "resources", "requests", "storage"); err != nil {
+				return err
+			}
+			kept = true
+		}
+		if !kept {
+			return nil
+		}
+		return unstructured.SetNestedSlice(obj.Object, templates, "spec", "volumeClaimTemplates")
+	}
+}
+
+// setResources merges the requests and limits provided into the resources in the given path.
+// Requests and limits that aren't in the size are kept
+func setResources(obj map[string]interface{}, resources map[string]interface{}, fields ...string) error {
+	for _, kind := range []string{"requests", "limits"} {
+		values, ok := resources[kind].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := append(append([]string{}, fields...), kind)
+		current, _, err := unstructured.NestedMap(obj, path...)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			current = map[string]interface{}{}
+		}
+		for name, value := range values {
+			current[name] = value
+		}
+		if err := unstructured.SetNestedMap(obj, current, path...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package install
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	imock "github.com/ForgeRock/forgeops-cli/internal/mock"
+	"github.com/ForgeRock/forgeops-cli/pkg/sizing"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSizeTransform(t *testing.T) {
+	size := sizing.Size{}
+	if err := yaml.Unmarshal([]byte(`
+name: small
+workloads:
+  - name: ds-idrepo
+    replicas: 3
+    container: ds
+    resources:
+      requests: {cpu: "2", memory: 4Gi}
+    storage: 100Gi
+  - name: am
+    replicas: 2
+`), &size); err != nil {
+		t.Fatal(err)
+	}
+	ds := newTestInfo(t, `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: ds-idrepo
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: ds
+        resources:
+          requests: {cpu: "1", memory: 1Gi}
+          limits: {memory: 2Gi}
+      - name: sidecar
+  volumeClaimTemplates:
+  - metadata:
+      name: backup
+    spec:
+      resources:
+        requests:
+          storage: 5Gi
+  - metadata:
+      name: data
+    spec:
+      resources:
+        requests:
+          storage: 10Gi
+`, meta.RESTScopeNamespace)
+	unsized := newTestInfo(t, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: idm
+spec:
+  replicas: 1
+`, meta.RESTScopeNamespace)
+
+	tf := SizeTransform(size)
+	if err := tf(ds); err != nil {
+		t.Fatal(err)
+	}
+	if err := tf(unsized); err != nil {
+		t.Fatal(err)
+	}
+
+	obj := ds.Object.(*unstructured.Unstructured).Object
+	if replicas, _, _ := unstructured.NestedInt64(obj, "spec", "replicas"); replicas != 3 {
+		t.Errorf("expected 3 replicas, found %d", replicas)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj, "spec", "template", "spec", "containers")
+	dsContainer := containers[0].(map[string]interface{})
+	expected := []struct {
+		fields []string
+		value  string
+	}{
+		{[]string{"resources", "requests", "cpu"}, "2"},
+		{[]string{"resources", "requests", "memory"}, "4Gi"},
+		// limits that aren't in the size are kept
+		{[]string{"resources", "limits", "memory"}, "2Gi"},
+	}
+	for _, e := range expected {
+		if v, _, _ := unstructured.NestedString(dsContainer, e.fields...); v != e.value {
+			t.Errorf("expected %v to be %q, found %q", e.fields, e.value, v)
+		}
+	}
+	if _, found := containers[1].(map[string]interface{})["resources"]; found {
+		t.Error("expected only the ds container to be sized")
+	}
+	templates, _, _ := unstructured.NestedSlice(obj, "spec", "volumeClaimTemplates")
+	if storage, _, _ := unstructured.NestedString(templates[1].(map[string]interface{}), "spec", "resources", "requests", "storage"); storage != "100Gi" {
+		t.Errorf("expected 100Gi of storage for the data claim, found %q", storage)
+	}
+	if storage, _, _ := unstructured.NestedString(templates[0].(map[string]interface{}), "spec", "resources", "requests", "storage"); storage != "5Gi" {
+		t.Errorf("expected the other claims to be left untouched, found %q", storage)
+	}
+
+	if replicas, _, _ := unstructured.NestedInt64(unsized.Object.(*unstructured.Unstructured).Object, "spec", "replicas"); replicas != 1 {
+		t.Errorf("expected workloads that aren't in the size to be left untouched, found %d replicas", replicas)
+	}
+}
+
+// TestKeepClaimStorage tests the storage of the StatefulSets in the cluster is kept on upgrade
+func TestKeepClaimStorage(t *testing.T) {
+	manifest := `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: ds-idrepo
+  namespace: prod
+spec:
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      resources:
+        requests:
+          storage: 100Gi
+`
+	live := newTestInfo(t, manifest, meta.RESTScopeNamespace).Object.(*unstructured.Unstructured)
+	unstructured.SetNestedSlice(live.Object, []interface{}{map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "data"},
+		"spec":     map[string]interface{}{"resources": map[string]interface{}{"requests": map[string]interface{}{"storage": "10Gi"}}},
+	}}, "spec", "volumeClaimTemplates")
+	f := &imock.Factory{}
+	f.On("DynamicClient").Return(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), live), nil)
+
+	upgraded := newTestInfo(t, manifest, meta.RESTScopeNamespace)
+	upgraded.Mapping.GroupVersionKind = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}
+	upgraded.Mapping.Resource = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+	created := newTestInfo(t, strings.Replace(manifest, "ds-idrepo", "ds-cts", 1), meta.RESTScopeNamespace)
+	created.Mapping = upgraded.Mapping
+
+	storage, err := liveClaimStorage(context.Background(), f, []*resource.Info{upgraded, created})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tf := keepClaimStorage(storage)
+	for _, info := range []*resource.Info{upgraded, created} {
+		if err := tf(info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	td := []struct {
+		// comment about test case
+		testComment string
+		info        *resource.Info
+		expected    string
+	}{
+		{testComment: "StatefulSets in the cluster keep their storage", info: upgraded, expected: "10Gi"},
+		{testComment: "StatefulSets not created yet are sized", info: created, expected: "100Gi"},
+	}
+	for _, tc := range td {
+		templates, _, _ := unstructured.NestedSlice(tc.info.Object.(*unstructured.Unstructured).Object, "spec", "volumeClaimTemplates")
+		if storage, _, _ := unstructured.NestedString(templates[0].(map[string]interface{}), "spec", "resources", "requests", "storage"); storage != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, storage)
+		}
+	}
+}
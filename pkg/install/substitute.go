@@ -0,0 +1,125 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// platformConfigName ConfigMap holding the FQDN and URLs of the platform
+const platformConfigName = "platform-config"
+
+// FQDNTransform replaces the placeholder FQDN in the Ingress hosts and TLS hosts, and in the platform-config ConfigMap
+func FQDNTransform(placeholder, fqdn string) TransformInfoFunc {
+	return func(info *resource.Info) error {
+		if placeholder == fqdn {
+			return nil
+		}
+		obj, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			return nil
+		}
+		changed := []string{}
+		switch obj.GetKind() {
+		case "Ingress":
+			rules, found, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+			if err != nil {
+				return err
+			}
+			for i, r := range rules {
+				rule, ok := r.(map[string]interface{})
+				if ok && rule["host"] == placeholder {
+					rule["host"] = fqdn
+					changed = append(changed, fmt.Sprintf("spec.rules[%d].host", i))
+				}
+			}
+			if found && len(changed) > 0 {
+				if err := unstructured.SetNestedSlice(obj.Object, rules, "spec", "rules"); err != nil {
+					return err
+				}
+			}
+			tls, found, err := unstructured.NestedSlice(obj.Object, "spec", "tls")
+			if err != nil {
+				return err
+			}
+			tlsChanged := false
+			for i, t := range tls {
+				entry, ok := t.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				hosts, _ := entry["hosts"].([]interface{})
+				for j, host := range hosts {
+					if host == placeholder {
+						hosts[j] = fqdn
+						tlsChanged = true
+						changed = append(changed, fmt.Sprintf("spec.tls[%d].hosts[%d]", i, j))
+					}
+				}
+			}
+			if found && tlsChanged {
+				if err := unstructured.SetNestedSlice(obj.Object, tls, "spec", "tls"); err != nil {
+					return err
+				}
+			}
+		case "ConfigMap":
+			if obj.GetName() != platformConfigName {
+				return nil
+			}
+			data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+			if err != nil {
+				return err
+			}
+			for key, value := range data {
+				// URLs of the platform, e.g. https://<fqdn>/am, are also kept in platform-config
+				if replaced := replaceHost(value, placeholder, fqdn); replaced != value {
+					data[key] = replaced
+					changed = append(changed, "data."+key)
+				}
+			}
+			if len(changed) == 0 {
+				return nil
+			}
+			if err := unstructured.SetNestedStringMap(obj.Object, data, "data"); err != nil {
+				return err
+			}
+		}
+		reportSubstitution(info, changed, fqdn)
+		return nil
+	}
+}
+
+// setNamespaces sets the namespace of namespaced objects whose namespace is the placeholder or empty.
+// The custom resources of the CRDs in infos take the scope of their CRD.
+// The namespace of RoleBinding and ClusterRoleBinding subjects is replaced as well
+func setNamespaces(infos []*resource.Info, placeholder, ns string) error {
+	crdScopes := k8s.CRDScopes(infos)
+	for _, info := range infos {
+		changed, err := k8s.SetNamespace(info, placeholder, ns, crdScopes)
+		if err != nil {
+			return err
+		}
+		reportSubstitution(info, changed, ns)
+	}
+	return nil
+}
+
+// replaceHost replaces the placeholder when it's the whole value or the host of a URL
+func replaceHost(value, placeholder, fqdn string) string {
+	if value == placeholder {
+		return fqdn
+	}
+	return strings.ReplaceAll(value, "://"+placeholder, "://"+fqdn)
+}
+
+func reportSubstitution(info *resource.Info, fields []string, value string) {
+	if len(fields) == 0 {
+		return
+	}
+	kind := info.Object.GetObjectKind().GroupVersionKind().Kind
+	printer.Noticef("%s %q: set %s to %q", kind, info.Name, strings.Join(fields, ", "), value)
+}
@@ -0,0 +1,204 @@
+package install
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// newTestInfo builds the info of the object in the given YAML. Objects without scope are unmapped,
+// like the custom resources of CRDs not established yet
+func newTestInfo(t *testing.T, manifest string, scope meta.RESTScope) *resource.Info {
+	data, err := yaml.YAMLToJSON([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	info := &resource.Info{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Object:    obj,
+	}
+	if scope != nil {
+		gvk := obj.GroupVersionKind()
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		info.Mapping = &meta.RESTMapping{Resource: gvr, GroupVersionKind: gvk, Scope: scope}
+	}
+	return info
+}
+
+func TestFQDNTransform(t *testing.T) {
+	ingress := newTestInfo(t, `
+apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: forgerock
+spec:
+  rules:
+  - host: default.iam.example.com
+  - host: other.example.com
+  tls:
+  - hosts:
+    - default.iam.example.com
+    secretName: sslcert
+`, meta.RESTScopeNamespace)
+	platformConfig := newTestInfo(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: platform-config
+data:
+  FQDN: default.iam.example.com
+  AM_URL: https://default.iam.example.com/am
+  DOMAIN: iam.example.com
+`, meta.RESTScopeNamespace)
+	other := newTestInfo(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+data:
+  comment: served from https://default.iam.example.com/am
+`, meta.RESTScopeNamespace)
+
+	tf := FQDNTransform("default.iam.example.com", "demo.customdomain.com")
+	for _, info := range []*resource.Info{ingress, platformConfig, other} {
+		if err := tf(info); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ing := ingress.Object.(*unstructured.Unstructured).Object
+	rules, _, _ := unstructured.NestedSlice(ing, "spec", "rules")
+	if host := rules[0].(map[string]interface{})["host"]; host != "demo.customdomain.com" {
+		t.Errorf("expected the rule host to be replaced, found %q", host)
+	}
+	if host := rules[1].(map[string]interface{})["host"]; host != "other.example.com" {
+		t.Errorf("expected other hosts to be left untouched, found %q", host)
+	}
+	tls, _, _ := unstructured.NestedSlice(ing, "spec", "tls")
+	if host := tls[0].(map[string]interface{})["hosts"].([]interface{})[0]; host != "demo.customdomain.com" {
+		t.Errorf("expected the TLS host to be replaced, found %q", host)
+	}
+
+	data, _, _ := unstructured.NestedStringMap(platformConfig.Object.(*unstructured.Unstructured).Object, "data")
+	expected := map[string]string{
+		"FQDN":   "demo.customdomain.com",
+		"AM_URL": "https://demo.customdomain.com/am",
+		"DOMAIN": "iam.example.com",
+	}
+	for k, v := range expected {
+		if data[k] != v {
+			t.Errorf("expected %s to be %q, found %q", k, v, data[k])
+		}
+	}
+
+	data, _, _ = unstructured.NestedStringMap(other.Object.(*unstructured.Unstructured).Object, "data")
+	if data["comment"] != "served from https://default.iam.example.com/am" {
+		t.Errorf("expected unrelated ConfigMaps to be left untouched, found %q", data["comment"])
+	}
+}
+
+func TestSetNamespaces(t *testing.T) {
+	placeholder := newTestInfo(t, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: placeholder
+  namespace: "default"
+`, meta.RESTScopeNamespace)
+	missing := newTestInfo(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: missing
+`, meta.RESTScopeNamespace)
+	explicit := newTestInfo(t, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: explicit
+  namespace: kube-system
+`, meta.RESTScopeNamespace)
+	binding := newTestInfo(t, `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: binding
+subjects:
+- kind: ServiceAccount
+  name: placeholder
+  namespace: default
+`, meta.RESTScopeRoot)
+
+	crd := newTestInfo(t, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusterissuers.cert-manager.io
+spec:
+  group: cert-manager.io
+  names:
+    kind: ClusterIssuer
+  scope: Cluster
+`, meta.RESTScopeRoot)
+	namespacedCRD := newTestInfo(t, `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: directoryservices.directory.forgerock.io
+spec:
+  group: directory.forgerock.io
+  names:
+    kind: DirectoryService
+  scope: Namespaced
+`, meta.RESTScopeRoot)
+	// Custom resources are unmapped until their CRD is established
+	clusterCR := newTestInfo(t, `
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: selfsigned
+`, nil)
+	namespacedCR := newTestInfo(t, `
+apiVersion: directory.forgerock.io/v1alpha1
+kind: DirectoryService
+metadata:
+  name: ds-idrepo
+  namespace: default
+`, nil)
+	unknown := newTestInfo(t, `
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: unknown
+`, nil)
+
+	infos := []*resource.Info{placeholder, missing, explicit, binding, crd, namespacedCRD, clusterCR, namespacedCR, unknown}
+	if err := setNamespaces(infos, "default", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range []*resource.Info{placeholder, missing, namespacedCR} {
+		if ns := info.Object.(*unstructured.Unstructured).GetNamespace(); ns != "prod" || info.Namespace != "prod" {
+			t.Errorf("expected %q to be in prod, found %q", info.Name, ns)
+		}
+	}
+	if explicit.Namespace != "kube-system" {
+		t.Errorf("expected explicit namespaces to be left untouched, found %q", explicit.Namespace)
+	}
+	for _, info := range []*resource.Info{binding, clusterCR, unknown} {
+		if ns := info.Object.(*unstructured.Unstructured).GetNamespace(); ns != "" || info.Namespace != "" {
+			t.Errorf("expected %q to have no namespace, found %q", info.Name, ns)
+		}
+	}
+	subjects, _, _ := unstructured.NestedSlice(binding.Object.(*unstructured.Unstructured).Object, "subjects")
+	if ns := subjects[0].(map[string]interface{})["namespace"]; ns != "prod" {
+		t.Errorf("expected the subject namespace to be replaced, found %q", ns)
+	}
+}
@@ -1,25 +1,60 @@
 package install
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
 	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/pkg/inventory"
 )
 
-// GHResource Installs resources listed in manifests publised on github
-func GHResource(clientFactory factory.Factory, ghRepo, fileName, version string) error {
-	fPath := fmt.Sprintf("https://github.com/%s/releases/latest/download/%s", ghRepo, fileName)
-	if len(version) == 0 {
-		version = "latest"
-	}
-	if version != "latest" {
-		fPath = fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", ghRepo, version, fileName)
+// GHResource Installs resources listed in manifests publised on github.
+// "latest" is resolved to the tag of the latest release
+func GHResource(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version string, opts Options) error {
+	version, err := release.ResolveVersion(ctx, ghRepo, version)
+	if err != nil {
+		return err
 	}
 	printer.Noticef("Installing %q version: %q", ghRepo, version)
-	if err := Manifest(clientFactory, fPath, standardTransforms()...); err != nil {
+	rm, err := RenderGHResource(ctx, clientFactory, ghRepo, fileName, version)
+	if err != nil {
+		return err
+	}
+	if err := Resources(ctx, clientFactory, rm.Infos, opts); err != nil {
+		return err
+	}
+	if opts.DryRun != DryRunNone {
+		printer.Noticef("Rendered %q version: %q (%s dry run)", ghRepo, version, opts.DryRun)
+		return nil
+	}
+	ns, err := k8s.NewK8sClientMgr(clientFactory).Namespace()
+	if err != nil {
+		return err
+	}
+	if err := recordInventory(ctx, clientFactory, ns, rm, opts); err != nil {
 		return err
 	}
 	printer.Noticef("Installed %q version: %q", ghRepo, version)
 	return nil
 }
+
+// RenderGHResource obtains the objects listed in the manifest as they are applied by GHResource
+func RenderGHResource(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version string) (*ReleaseManifest, error) {
+	version, err := release.ResolveVersion(ctx, ghRepo, version)
+	if err != nil {
+		return nil, err
+	}
+	manifestStr, err := release.Fetch(ctx, ghRepo, version, fileName)
+	if err != nil {
+		return nil, err
+	}
+	rm := newReleaseManifest(ghRepo, fileName, version, manifestStr)
+	transforms := append(standardTransforms(inventory.ComponentName(fileName)),
+		ProvenanceTransform(release.Location(ghRepo, version, fileName), version, rm.Checksum))
+	if rm.Infos, err = Render(clientFactory, manifestStr, transforms...); err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
@@ -0,0 +1,243 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/internal/release"
+	"github.com/ForgeRock/forgeops-cli/internal/utils"
+	"github.com/ForgeRock/forgeops-cli/pkg/doctor"
+	"github.com/ForgeRock/forgeops-cli/pkg/health"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// rolloutChecks make sure the workloads are running the upgraded spec, not only available
+var rolloutChecks = map[string]string{
+	"deployments":  "status.observedGeneration == metadata.generation && status.updatedReplicas == spec.replicas",
+	"statefulsets": "status.observedGeneration == metadata.generation && status.updateRevision == status.currentRevision",
+}
+
+// Upgrade moves the CDQ installed in the namespace to the given version tier by tier.
+// Each tier must pass the platform health checks within the timeout before the next tier is upgraded.
+// The overall budget of the options bounds the health checks as well. The resources of a tier that doesn't
+// become healthy are diagnosed. The amster job isn't run again, the configuration it imported is kept.
+// The storage of the StatefulSets is kept, their volume claim templates can't be changed
+func Upgrade(ctx context.Context, clientFactory factory.Factory, ghRepo, version, fqdn string, timeout time.Duration, opts Options) error {
+	opts = opts.startTimeout(time.Now())
+	version, err := release.ResolveVersion(ctx, ghRepo, version)
+	if err != nil {
+		return err
+	}
+	p := profile.Current()
+	records, err := tierRecords(ctx, clientFactory, p)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errors.New("the CDQ wasn't installed in this namespace by the forgeops-cli. Use \"forgeops install quickstart\" instead")
+	}
+	if len(fqdn) == 0 {
+		fqdn = records[0].FQDN
+	}
+	if err := upgradeTiers(ctx, clientFactory, p, p.Spec.Tiers, ghRepo, version, fqdn, timeout, opts); err != nil {
+		return err
+	}
+	if opts.DryRun != DryRunNone {
+		printer.Noticef("CDQ upgrade dry run complete. No changes were made")
+		return nil
+	}
+	printer.Noticef("CDQ upgraded to version %q", version)
+	return nil
+}
+
+// tierOperation names the operation moving the tiers to another version in notices and errors
+type tierOperation struct {
+	name        string
+	progressive string
+	past        string
+}
+
+func (o Options) tierOperation() tierOperation {
+	if o.rollback {
+		return tierOperation{name: "rollback", progressive: "Rolling back", past: "rolled back"}
+	}
+	return tierOperation{name: "upgrade", progressive: "Upgrading", past: "upgraded"}
+}
+
+// upgradeTiers applies the components of the given tiers at the given version in order, see Upgrade.
+// Rollbacks move the tiers the same way
+func upgradeTiers(ctx context.Context, clientFactory factory.Factory, p *profile.Profile, tiers []profile.Tier, ghRepo, version, fqdn string, timeout time.Duration, opts Options) error {
+	ns, err := k8s.NewK8sClientMgr(clientFactory).Namespace()
+	if err != nil {
+		return err
+	}
+	amster, err := p.Component("amster")
+	if err != nil {
+		return err
+	}
+	amsterJobs, err := jobs(ctx, clientFactory, ghRepo, amster.Manifest, version, fqdn)
+	if err != nil {
+		return err
+	}
+
+	op := opts.tierOperation()
+	printer.NoticeHif("Targeting namespace: %q", ns)
+	for i, tier := range tiers {
+		printer.NoticeHif("%s tier %q to version %q", op.progressive, tier.Name, version)
+		applied := []*resource.Info{}
+		for _, componentName := range tier.Components {
+			component, err := p.Component(componentName)
+			if err != nil {
+				return err
+			}
+			rm, err := RenderForgeRockComponent(ctx, clientFactory, ghRepo, component.Manifest, version, fqdn)
+			if err != nil {
+				return err
+			}
+			rm.Infos = withoutObjects(rm.Infos, amsterJobs)
+			if len(rm.Infos) == 0 {
+				continue
+			}
+			componentOpts := opts
+			if componentOpts.liveStorage, err = liveClaimStorage(ctx, clientFactory, rm.Infos); err != nil {
+				return upgradeInterrupted(ctx, op, tiers, i, err)
+			}
+			if err := Resources(ctx, clientFactory, rm.Infos, componentOpts); err != nil {
+				return upgradeInterrupted(ctx, op, tiers, i, err)
+			}
+			if opts.DryRun == DryRunNone {
+				if err := recordInventory(ctx, clientFactory, ns, rm, opts); err != nil {
+					return upgradeInterrupted(ctx, op, tiers, i, err)
+				}
+			}
+			applied = append(applied, rm.Infos...)
+		}
+		// Nothing was persisted. There's nothing to wait for
+		if opts.DryRun != DryRunNone {
+			continue
+		}
+		remaining := tierNames(tiers[i+1:])
+		tierTimeout, budget := healthTimeout(timeout, opts, time.Now())
+		if tierTimeout <= 0 {
+			return fmt.Errorf("%s ran out before tier %q became healthy. The %s was stopped, tiers not %s: [%s]",
+				budget, tier.Name, op.name, op.past, strings.Join(remaining, ", "))
+		}
+		printer.Noticef("Waiting for tier %q to become healthy. This can take several minutes", tier.Name)
+		hlth, err := tierHealth(tier.Name, applied, tierTimeout)
+		if err != nil {
+			return err
+		}
+		healthy, err := health.Run(ctx, clientFactory, hlth, false)
+		if err != nil {
+			return upgradeInterrupted(ctx, op, tiers, i, err)
+		}
+		if !healthy {
+			for _, r := range hlth.Unhealthy() {
+				gvr := schema.GroupVersionResource{Group: r.Group, Version: r.APIVersion, Resource: r.Resource}
+				reportDiagnosis(ctx, clientFactory, gvr, ns, r.Name, opts.Diagnostics)
+			}
+			return fmt.Errorf("tier %q didn't become healthy within %s: %s ran out. The %s was stopped, tiers not %s: [%s]",
+				tier.Name, tierTimeout.Round(time.Second), budget, op.name, op.past, strings.Join(remaining, ", "))
+		}
+	}
+	return nil
+}
+
+// upgradeInterrupted reports the tier being upgraded when ctx is cancelled, otherwise err is returned as is
+func upgradeInterrupted(ctx context.Context, op tierOperation, tiers []profile.Tier, i int, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+	printer.Warnf("Interrupted the %s of tier %q. It may be partially %s", op.name, tiers[i].Name, op.past)
+	return fmt.Errorf("%s of tier %q was interrupted: %w. Tiers not %s: [%s]",
+		op.name, tiers[i].Name, ctx.Err(), op.past, strings.Join(tierNames(tiers[i+1:]), ", "))
+}
+
+func tierNames(tiers []profile.Tier) []string {
+	names := []string{}
+	for _, t := range tiers {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// healthTimeout resolves the time a tier is given to become healthy at the given time.
+// What's left of the overall budget bounds the health timeout
+func healthTimeout(timeout time.Duration, opts Options, now time.Time) (time.Duration, string) {
+	budget := fmt.Sprintf("the --health-timeout of %s", timeout)
+	if !opts.deadline.IsZero() {
+		if left := opts.deadline.Sub(now); left < timeout {
+			return left, fmt.Sprintf("the overall --timeout of %s", opts.Timeout)
+		}
+	}
+	return timeout, budget
+}
+
+// tierHealth selects the checks of the platform health that apply to the objects of the tier.
+// Workloads must also have rolled out the upgraded spec. Every check waits up to the timeout provided
+func tierHealth(tierName string, applied []*resource.Info, timeout time.Duration) (*health.Health, error) {
+	platformHealth, err := health.GetHealthFromBytes(doctor.DefaultPlatformHealth)
+	if err != nil {
+		return nil, err
+	}
+	hlth := &health.Health{}
+	hlth.Metadata.Name = fmt.Sprintf("%s/%s", platformHealth.Metadata.Name, tierName)
+	for _, r := range platformHealth.Spec.Resources {
+		for _, info := range applied {
+			if info.Mapping == nil || info.Mapping.Resource.Resource != r.Resource || info.Name != r.Name {
+				continue
+			}
+			if expression, ok := rolloutChecks[r.Resource]; ok {
+				r.Checks = append([]*health.Check{{Expression: expression}}, r.Checks...)
+			}
+			for _, check := range r.Checks {
+				check.Timeout = metav1.Duration{Duration: timeout}
+			}
+			hlth.Spec.Resources = append(hlth.Spec.Resources, r)
+			break
+		}
+	}
+	return hlth, nil
+}
+
+// jobs returns the jobs of the given component. Releases that don't publish the component have no jobs
+func jobs(ctx context.Context, clientFactory factory.Factory, ghRepo, fileName, version, fqdn string) ([]*resource.Info, error) {
+	rm, err := RenderForgeRockComponent(ctx, clientFactory, ghRepo, fileName, version, fqdn)
+	if errors.Is(err, utils.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	found := []*resource.Info{}
+	for _, info := range rm.Infos {
+		if info.Object.GetObjectKind().GroupVersionKind().Kind == "Job" {
+			found = append(found, info)
+		}
+	}
+	return found, nil
+}
+
+// withoutObjects returns the objects that aren't in the excluded list
+func withoutObjects(infos, excluded []*resource.Info) []*resource.Info {
+	excludedKeys := map[string]bool{}
+	for _, info := range excluded {
+		excludedKeys[objectKey(info)] = true
+	}
+	filtered := []*resource.Info{}
+	for _, info := range infos {
+		if !excludedKeys[objectKey(info)] {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered
+}
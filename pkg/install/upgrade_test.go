@@ -0,0 +1,88 @@
+package install
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// TestTierHealth tests only the checks of the tier objects are selected
+func TestTierHealth(t *testing.T) {
+	applied := []*resource.Info{
+		newTestInfo(t, fmt.Sprintf(testDeployment, "am", "apps"), meta.RESTScopeNamespace),
+		newTestInfo(t, fmt.Sprintf(testDeployment, "not-checked", "apps"), meta.RESTScopeNamespace),
+	}
+	hlth, err := tierHealth("apps", applied, 10*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hlth.Spec.Resources) != 1 {
+		t.Fatalf("expected the checks of 1 resource, found %d", len(hlth.Spec.Resources))
+	}
+	am := hlth.Spec.Resources[0]
+	if am.Name != "am" {
+		t.Errorf("expected the checks of am, found %q", am.Name)
+	}
+	// rollout check followed by the platform health check
+	if len(am.Checks) != 2 || am.Checks[0].Expression != rolloutChecks["deployments"] {
+		t.Fatalf("expected the rollout check first, found %+v", am.Checks)
+	}
+	for _, check := range am.Checks {
+		if check.Timeout.Duration != 10*time.Minute {
+			t.Errorf("expected a timeout of 10m, found %s", check.Timeout.Duration)
+		}
+	}
+}
+
+// TestHealthTimeout tests the overall budget left bounds the time a tier is given to become healthy
+func TestHealthTimeout(t *testing.T) {
+	now := time.Now()
+	td := []struct {
+		// comment about test case
+		testComment string
+		opts        Options
+		expected    time.Duration
+		budget      string
+	}{
+		{
+			testComment: "the health timeout is used without an overall budget",
+			opts:        Options{},
+			expected:    10 * time.Minute,
+			budget:      "the --health-timeout of 10m0s",
+		},
+		{
+			testComment: "the overall budget left bounds the health timeout",
+			opts:        Options{Timeout: 30 * time.Minute, deadline: now.Add(5 * time.Minute)},
+			expected:    5 * time.Minute,
+			budget:      "the overall --timeout of 30m0s",
+		},
+		{
+			testComment: "a larger overall budget doesn't extend the health timeout",
+			opts:        Options{Timeout: time.Hour, deadline: now.Add(time.Hour)},
+			expected:    10 * time.Minute,
+			budget:      "the --health-timeout of 10m0s",
+		},
+	}
+	for _, tc := range td {
+		timeout, budget := healthTimeout(10*time.Minute, tc.opts, now)
+		if timeout != tc.expected || budget != tc.budget {
+			t.Errorf("%s expected: %s and %q, found: %s and %q", tc.testComment, tc.expected, tc.budget, timeout, budget)
+		}
+	}
+}
+
+// TestWithoutObjects tests excluded objects are filtered out
+func TestWithoutObjects(t *testing.T) {
+	infos := []*resource.Info{
+		newTestInfo(t, fmt.Sprintf(testDeployment, "am", "apps"), meta.RESTScopeNamespace),
+		newTestInfo(t, fmt.Sprintf(testDeployment, "amster", "apps"), meta.RESTScopeNamespace),
+	}
+	amster := newTestInfo(t, fmt.Sprintf(testDeployment, "amster", "amster"), meta.RESTScopeNamespace)
+	filtered := withoutObjects(infos, []*resource.Info{amster})
+	if len(filtered) != 1 || filtered[0].Name != "am" {
+		t.Errorf("expected only am, found %d objects", len(filtered))
+	}
+}
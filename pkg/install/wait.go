@@ -0,0 +1,168 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+	"github.com/ForgeRock/forgeops-cli/pkg/delete"
+	"github.com/ForgeRock/forgeops-cli/pkg/diagnose"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// waitFor waits for the object to meet the condition of the wait. Waits without condition only wait for the object to exist.
+// The object is diagnosed when the wait fails. A wait that times out reports the budget that ran out
+func waitFor(ctx context.Context, clientFactory factory.Factory, w profile.Wait, opts Options) error {
+	gvr, err := w.GroupVersionResource()
+	if err != nil {
+		return err
+	}
+	k8sCntMgr := k8s.NewK8sClientMgr(clientFactory)
+	ns, err := k8sCntMgr.Namespace()
+	if err != nil {
+		return err
+	}
+	timeout, budget := waitTimeout(w, opts, time.Now())
+	if timeout <= 0 {
+		return fmt.Errorf("%s ran out before waiting for %s %q. %s", budget.name, w.Resource, w.Name, budget.hint)
+	}
+	// The watches time out in seconds. The overall budget bounds them as well
+	seconds := int(math.Ceil(timeout.Seconds()))
+	waitCtx := ctx
+	if !opts.deadline.IsZero() {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithDeadline(ctx, opts.deadline)
+		defer cancel()
+	}
+	var met bool
+	if len(w.Condition) == 0 {
+		printer.Noticef("Waiting for %s %q to be created. Timeout: %s", w.Resource, w.Name, timeout.Round(time.Second))
+		met, err = k8sCntMgr.WaitForResource(waitCtx, seconds, ns, w.Name, gvr)
+	} else {
+		printer.Noticef("Waiting for %s %q to meet %q. Timeout: %s", w.Resource, w.Name, w.Condition, timeout.Round(time.Second))
+		wp := newWaitProgress(ctx, clientFactory, gvr)
+		progress := printer.StartProgress(fmt.Sprintf("%s %q", w.Resource, w.Name), timeout, wp.status)
+		met, err = k8sCntMgr.WatchEventsForCondition(waitCtx, seconds, ns, w.Name, gvr, wp.observe(k8s.ConditionExpression(w.Condition)))
+		progress.Stop()
+	}
+	if err == nil && met {
+		return nil
+	}
+	// Nothing is diagnosed after an interruption
+	if ctx.Err() != nil {
+		return fmt.Errorf("stopped waiting for %s %q: %w", w.Resource, w.Name, ctx.Err())
+	}
+	reportDiagnosis(ctx, clientFactory, gvr, ns, w.Name, opts.Diagnostics)
+	// The overall budget ran out when only the wait context expired
+	if waitCtx.Err() != nil {
+		_, budget = waitTimeout(w, opts, opts.deadline)
+		return fmt.Errorf("%s %q wasn't ready before the deadline: %s ran out. %s", w.Resource, w.Name, budget.name, budget.hint)
+	}
+	if err != nil && !timedOut(err) {
+		return fmt.Errorf("error waiting for %s %q: %w", w.Resource, w.Name, err)
+	}
+	if len(w.Condition) == 0 {
+		return fmt.Errorf("%s %q wasn't created within %s: %s ran out. %s",
+			w.Resource, w.Name, timeout.Round(time.Second), budget.name, budget.hint)
+	}
+	return fmt.Errorf("%s %q didn't meet %q within %s: %s ran out. %s",
+		w.Resource, w.Name, w.Condition, timeout.Round(time.Second), budget.name, budget.hint)
+}
+
+// waitBudget describes the budget bounding a wait and how to extend it
+type waitBudget struct {
+	name string
+	hint string
+}
+
+// waitTimeout resolves the timeout of the wait from the timeout of the profile, the override of its stage
+// and what's left of the overall budget at the given time. The smallest one bounds the wait
+func waitTimeout(w profile.Wait, opts Options, now time.Time) (time.Duration, waitBudget) {
+	stage := w.StageName()
+	timeout := w.Timeout.Duration
+	budget := waitBudget{
+		name: fmt.Sprintf("the %s timeout of stage %q from the profile", timeout, stage),
+		hint: fmt.Sprintf("Use --wait-timeout %s=<duration> to wait longer", stage),
+	}
+	if t, ok := opts.WaitTimeouts[stage]; ok {
+		timeout = t
+		budget.name = fmt.Sprintf("the --wait-timeout of %s for stage %q", t, stage)
+	}
+	if !opts.deadline.IsZero() {
+		if left := opts.deadline.Sub(now); left < timeout {
+			timeout = left
+			budget = waitBudget{
+				name: fmt.Sprintf("the overall --timeout of %s", opts.Timeout),
+				hint: "Use a larger --timeout to wait longer",
+			}
+		}
+	}
+	return timeout, budget
+}
+
+// ParseWaitTimeouts validates the values of the --wait-timeout flag, e.g. ds=20m.
+// Each stage must be the stage of a wait of the profile
+func ParseWaitTimeouts(values map[string]string, p *profile.Profile) (map[string]time.Duration, error) {
+	stages := map[string]bool{}
+	for _, stage := range p.Stages() {
+		stages[stage] = true
+	}
+	timeouts := map[string]time.Duration{}
+	for stage, value := range values {
+		if !stages[stage] {
+			return nil, fmt.Errorf("unknown wait stage %q. Allowed stages are: %s", stage, strings.Join(p.Stages(), ", "))
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wait timeout %q for stage %q: %w", value, stage, err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("the wait timeout of stage %q must be positive, found: %s", stage, timeout)
+		}
+		timeouts[stage] = timeout
+	}
+	return timeouts, nil
+}
+
+// timedOut reports whether the wait stopped because its timeout expired
+func timedOut(err error) bool {
+	return errors.Is(err, wait.ErrWaitTimeout) || errors.Is(err, k8s.ErrWatchTimeout)
+}
+
+// reportDiagnosis prints the diagnosis of an object that didn't become ready and writes it to the diagnostics file.
+// Failing to diagnose the object doesn't hide the failed wait
+func reportDiagnosis(ctx context.Context, clientFactory factory.Factory, gvr schema.GroupVersionResource, ns, name string, opts diagnose.Options) {
+	d, err := diagnose.Object(ctx, clientFactory, gvr, ns, name, opts)
+	if err != nil {
+		printer.Warnf("Couldn't diagnose %s %q: %s", gvr.Resource, name, err)
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(d.String(), "\n"), "\n") {
+		printer.Warnln(line)
+	}
+	if len(opts.File) == 0 {
+		return
+	}
+	if err := d.WriteFile(opts.File); err != nil {
+		printer.Warnf("Couldn't write the diagnosis to %s: %s", opts.File, err)
+		return
+	}
+	printer.Noticef("Diagnosis written to %s", opts.File)
+}
+
+// cleanupComponent deletes a component that's only needed until the waits of its tier are met, e.g. amster
+func cleanupComponent(ctx context.Context, clientFactory factory.Factory, p *profile.Profile, componentName, ghRepo, version string) error {
+	component, err := p.Component(componentName)
+	if err != nil {
+		return err
+	}
+	return delete.ForgeRockComponent(ctx, clientFactory, ghRepo, component.Manifest, version, true)
+}
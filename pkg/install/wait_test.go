@@ -0,0 +1,99 @@
+package install
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestWaitTimeout tests the smallest of the profile timeout, the stage override and the overall budget bounds the wait
+func TestWaitTimeout(t *testing.T) {
+	now := time.Now()
+	ds := profile.Wait{Name: "ds-idrepo", Stage: "ds", Timeout: metav1.Duration{Duration: 10 * time.Minute}}
+	td := []struct {
+		// comment about test case
+		testComment string
+		opts        Options
+		expected    time.Duration
+		budget      string
+	}{
+		{
+			testComment: "the profile timeout is used by default",
+			opts:        Options{},
+			expected:    10 * time.Minute,
+			budget:      `the 10m0s timeout of stage "ds" from the profile`,
+		},
+		{
+			testComment: "the stage override replaces the profile timeout",
+			opts:        Options{WaitTimeouts: map[string]time.Duration{"ds": 20 * time.Minute, "am": time.Minute}},
+			expected:    20 * time.Minute,
+			budget:      `the --wait-timeout of 20m0s for stage "ds"`,
+		},
+		{
+			testComment: "the overall budget left bounds the stage timeout",
+			opts:        Options{Timeout: 30 * time.Minute, WaitTimeouts: map[string]time.Duration{"ds": 20 * time.Minute}, deadline: now.Add(5 * time.Minute)},
+			expected:    5 * time.Minute,
+			budget:      "the overall --timeout of 30m0s",
+		},
+		{
+			testComment: "a larger overall budget doesn't extend the stage timeout",
+			opts:        Options{Timeout: time.Hour, deadline: now.Add(time.Hour)},
+			expected:    10 * time.Minute,
+			budget:      `the 10m0s timeout of stage "ds" from the profile`,
+		},
+	}
+	for _, tc := range td {
+		timeout, budget := waitTimeout(ds, tc.opts, now)
+		if timeout != tc.expected || budget.name != tc.budget {
+			t.Errorf("%s expected: %s and %q, found: %s and %q", tc.testComment, tc.expected, tc.budget, timeout, budget.name)
+		}
+	}
+}
+
+// TestStartTimeout tests the overall budget is only started once
+func TestStartTimeout(t *testing.T) {
+	now := time.Now()
+	if opts := (Options{}).startTimeout(now); !opts.deadline.IsZero() {
+		t.Errorf("expected no deadline without an overall timeout, found: %s", opts.deadline)
+	}
+	opts := Options{Timeout: time.Minute}.startTimeout(now)
+	if !opts.deadline.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the deadline in a minute, found: %s", opts.deadline)
+	}
+	if restarted := opts.startTimeout(now.Add(time.Hour)); !restarted.deadline.Equal(opts.deadline) {
+		t.Errorf("expected the deadline to be kept, found: %s", restarted.deadline)
+	}
+}
+
+// TestParseWaitTimeouts tests the --wait-timeout values must name stages of the profile and positive durations
+func TestParseWaitTimeouts(t *testing.T) {
+	p := profile.Current()
+	td := []struct {
+		// comment about test case
+		testComment string
+		values      map[string]string
+		expectErr   bool
+	}{
+		{testComment: "stages of the profile are accepted", values: map[string]string{"ds": "20m", "am": "15m"}, expectErr: false},
+		{testComment: "stages shared by several waits are accepted", values: map[string]string{"secrets": "1m"}, expectErr: false},
+		{testComment: "unknown stages are rejected", values: map[string]string{"idm": "5m"}, expectErr: true},
+		{testComment: "invalid durations are rejected", values: map[string]string{"ds": "twenty"}, expectErr: true},
+		{testComment: "non positive durations are rejected", values: map[string]string{"ds": "0s"}, expectErr: true},
+	}
+	for _, tc := range td {
+		timeouts, err := ParseWaitTimeouts(tc.values, p)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+			continue
+		}
+		if err != nil && strings.Contains(err.Error(), "unknown wait stage") && !strings.Contains(err.Error(), "git-server") {
+			t.Errorf("%s expected the stages of the profile in the error, found: %s", tc.testComment, err)
+		}
+		if err == nil && len(timeouts) != len(tc.values) {
+			t.Errorf("%s expected %d timeouts, found: %v", tc.testComment, len(tc.values), timeouts)
+		}
+	}
+}
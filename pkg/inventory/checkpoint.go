@@ -0,0 +1,119 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CheckpointLabel identifies the ConfigMap holding the quickstart checkpoint
+	CheckpointLabel = "forgeops-cli.forgerock.com/checkpoint"
+	// checkpointConfigMap name of the ConfigMap holding the quickstart checkpoint
+	checkpointConfigMap = "forgeops-quickstart-checkpoint"
+)
+
+// Checkpoint records the quickstart steps completed in a namespace so an interrupted install can be resumed
+type Checkpoint struct {
+	GHRepo    string      `json:"ghRepo"`
+	Version   string      `json:"version"`
+	FQDN      string      `json:"fqdn,omitempty"`
+	Completed []string    `json:"completed"`
+	UpdatedAt metav1.Time `json:"updatedAt"`
+}
+
+// Done is true when the given step completed
+func (c Checkpoint) Done(step string) bool {
+	for _, s := range c.Completed {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCheckpoint returns the quickstart checkpoint of the current namespace.
+// A NotFound error is returned when there's no checkpoint
+func GetCheckpoint(ctx context.Context, clientFactory factory.Factory) (Checkpoint, error) {
+	ns, err := k8s.NewK8sClientMgr(clientFactory).Namespace()
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	cm, err := sclient.CoreV1().ConfigMaps(ns).Get(ctx, checkpointConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	checkpoint := Checkpoint{}
+	if err := json.Unmarshal([]byte(cm.Data["checkpoint"]), &checkpoint); err != nil {
+		return Checkpoint{}, fmt.Errorf("invalid quickstart checkpoint %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return checkpoint, nil
+}
+
+// WriteCheckpoint stores the quickstart checkpoint in the current namespace
+func WriteCheckpoint(ctx context.Context, clientFactory factory.Factory, checkpoint Checkpoint) error {
+	ns, err := k8s.NewK8sClientMgr(clientFactory).Namespace()
+	if err != nil {
+		return err
+	}
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return err
+	}
+	checkpoint.UpdatedAt = metav1.Now()
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	configMaps := sclient.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(ctx, checkpointConfigMap, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if !exists {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      checkpointConfigMap,
+				Namespace: ns,
+				Labels:    map[string]string{CheckpointLabel: "true"},
+			},
+		}
+	}
+	cm.Data = map[string]string{
+		"checkpoint": string(data),
+	}
+	if exists {
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{FieldManager: "forgeops-cli"})
+		return err
+	}
+	_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{FieldManager: "forgeops-cli"})
+	return err
+}
+
+// DeleteCheckpoint removes the quickstart checkpoint from the current namespace
+func DeleteCheckpoint(ctx context.Context, clientFactory factory.Factory) error {
+	ns, err := k8s.NewK8sClientMgr(clientFactory).Namespace()
+	if err != nil {
+		return err
+	}
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return err
+	}
+	err = sclient.CoreV1().ConfigMaps(ns).Delete(ctx, checkpointConfigMap, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
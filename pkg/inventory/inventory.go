@@ -0,0 +1,257 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ForgeRock/forgeops-cli/internal/factory"
+	"github.com/ForgeRock/forgeops-cli/internal/k8s"
+	"github.com/ForgeRock/forgeops-cli/pkg/profile"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+const (
+	// InventoryLabel identifies the ConfigMaps holding inventory records
+	InventoryLabel = "forgeops-cli.forgerock.com/inventory"
+	// configMapPrefix prefix of the inventory ConfigMap of each component
+	configMapPrefix = "forgeops-inventory-"
+	// maxHistory number of previous installs kept in the history of a component
+	maxHistory = 10
+)
+
+// ObjectRef reference to an object installed with a component
+type ObjectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// Record describes a component installed by the forgeops-cli
+type Record struct {
+	Namespace   string      `json:"namespace"`
+	Component   string      `json:"component"`
+	GHRepo      string      `json:"ghRepo"`
+	Manifest    string      `json:"manifest"`
+	Version     string      `json:"version"`
+	Checksum    string      `json:"checksum"`
+	FQDN        string      `json:"fqdn,omitempty"`
+	CLIVersion  string      `json:"cliVersion"`
+	InstalledAt metav1.Time `json:"installedAt"`
+	Objects     []ObjectRef `json:"objects"`
+	// RolledBack the version was installed by a rollback
+	RolledBack bool `json:"rolledBack,omitempty"`
+	// History previous installs of the component, newest first
+	History []Record `json:"history,omitempty"`
+}
+
+// ComponentName returns the name of the component published in the given manifest.
+// The manifests of the profile components are named after their component, e.g. directory for ds.yaml.
+// Other manifests are named after the file, e.g. secret-agent for secret-agent.yaml
+func ComponentName(fileName string) string {
+	if c, err := profile.Current().ManifestComponent(fileName); err == nil {
+		return c.Name
+	}
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName))
+}
+
+// PreviousVersion returns the version installed before the current version of the component.
+// The installs are replayed oldest first: a rollback returns to the version it rolled back to, so rolling back
+// twice goes further back instead of returning to the version rolled back from
+func PreviousVersion(record Record) (string, error) {
+	installs := append([]Record{record}, record.History...)
+	versions := []string{}
+	for i := len(installs) - 1; i >= 0; i-- {
+		r := installs[i]
+		if len(versions) > 0 && versions[len(versions)-1] == r.Version {
+			continue
+		}
+		if r.RolledBack {
+			if j := lastIndex(versions, r.Version); j >= 0 {
+				versions = versions[:j+1]
+				continue
+			}
+		}
+		versions = append(versions, r.Version)
+	}
+	if len(versions) < 2 {
+		return "", fmt.Errorf("no version of %q was installed before %q", record.Component, record.Version)
+	}
+	return versions[len(versions)-2], nil
+}
+
+func lastIndex(versions []string, version string) int {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i] == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// ObjectRefs returns references to the objects provided
+func ObjectRefs(infos []*resource.Info) []ObjectRef {
+	refs := make([]ObjectRef, 0, len(infos))
+	for _, info := range infos {
+		gvk := info.Object.GetObjectKind().GroupVersionKind()
+		refs = append(refs, ObjectRef{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  info.Namespace,
+			Name:       info.Name,
+		})
+	}
+	return refs
+}
+
+// Write stores the record in the namespace of the record.
+// The record previously stored for the component is kept in the history
+func Write(ctx context.Context, clientFactory factory.Factory, record Record) error {
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return err
+	}
+	configMaps := sclient.CoreV1().ConfigMaps(record.Namespace)
+	name := configMapPrefix + record.Component
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists {
+		if previous, err := fromConfigMap(cm); err == nil {
+			record.History = history(previous)
+		}
+	} else {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: record.Namespace,
+			},
+		}
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if cm.Labels == nil {
+		cm.Labels = map[string]string{}
+	}
+	cm.Labels[InventoryLabel] = "true"
+	cm.Data = map[string]string{
+		"record": string(data),
+	}
+	if exists {
+		_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{FieldManager: "forgeops-cli"})
+		return err
+	}
+	_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{FieldManager: "forgeops-cli"})
+	return err
+}
+
+// Get returns the record of the given component in the current namespace
+func Get(ctx context.Context, clientFactory factory.Factory, component string) (Record, error) {
+	ns, err := k8s.NewK8sClientMgr(clientFactory).Namespace()
+	if err != nil {
+		return Record{}, err
+	}
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return Record{}, err
+	}
+	cm, err := sclient.CoreV1().ConfigMaps(ns).Get(ctx, configMapPrefix+component, metav1.GetOptions{})
+	if err != nil {
+		return Record{}, err
+	}
+	return fromConfigMap(cm)
+}
+
+// List returns the records stored in the current namespace or in all namespaces
+func List(ctx context.Context, clientFactory factory.Factory, allNamespaces bool) ([]Record, error) {
+	ns := metav1.NamespaceAll
+	if !allNamespaces {
+		var err error
+		if ns, err = k8s.NewK8sClientMgr(clientFactory).Namespace(); err != nil {
+			return nil, err
+		}
+	}
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return nil, err
+	}
+	cms, err := sclient.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{LabelSelector: InventoryLabel + "=true"})
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	for i := range cms.Items {
+		record, err := fromConfigMap(&cms.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Namespace != records[j].Namespace {
+			return records[i].Namespace < records[j].Namespace
+		}
+		return records[i].InstalledAt.Before(&records[j].InstalledAt)
+	})
+	return records, nil
+}
+
+// Delete removes the record of the given component from the current namespace
+func Delete(ctx context.Context, clientFactory factory.Factory, component string) error {
+	ns, err := k8s.NewK8sClientMgr(clientFactory).Namespace()
+	if err != nil {
+		return err
+	}
+	sclient, err := clientFactory.StaticClient()
+	if err != nil {
+		return err
+	}
+	err = sclient.CoreV1().ConfigMaps(ns).Delete(ctx, configMapPrefix+component, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteAll removes every record from the current namespace
+func DeleteAll(ctx context.Context, clientFactory factory.Factory) error {
+	records, err := List(ctx, clientFactory, false)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := Delete(ctx, clientFactory, record.Component); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// history returns the history of the record replacing the previous record, newest first
+func history(previous Record) []Record {
+	h := append([]Record{previous}, previous.History...)
+	h[0].History = nil
+	if len(h) > maxHistory {
+		h = h[:maxHistory]
+	}
+	return h
+}
+
+func fromConfigMap(cm *corev1.ConfigMap) (Record, error) {
+	record := Record{}
+	if err := json.Unmarshal([]byte(cm.Data["record"]), &record); err != nil {
+		return Record{}, fmt.Errorf("invalid inventory record %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return record, nil
+}
@@ -0,0 +1,97 @@
+package inventory
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestComponentName(t *testing.T) {
+	tests := map[string]string{
+		"base.yaml":         "base",
+		"secret-agent.yaml": "secret-agent",
+		"ds-cts":            "ds-cts",
+		// the directory component is published in ds.yaml
+		"ds.yaml": "directory",
+	}
+	for fileName, expected := range tests {
+		if got := ComponentName(fileName); got != expected {
+			t.Errorf("ComponentName(%q) = %q, expected %q", fileName, got, expected)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	previous := Record{Version: "v1"}
+	h := history(previous)
+	if len(h) != 1 || h[0].Version != "v1" {
+		t.Fatalf("unexpected history %+v", h)
+	}
+
+	for i := 2; i <= maxHistory+5; i++ {
+		previous = Record{Version: fmt.Sprintf("v%d", i), History: h}
+		h = history(previous)
+	}
+	if len(h) != maxHistory {
+		t.Fatalf("expected %d records, found %d", maxHistory, len(h))
+	}
+	if h[0].Version != fmt.Sprintf("v%d", maxHistory+5) {
+		t.Errorf("expected the newest record first, found %q", h[0].Version)
+	}
+	for _, r := range h {
+		if len(r.History) != 0 {
+			t.Errorf("history records must not be nested, found %d in %q", len(r.History), r.Version)
+		}
+	}
+}
+
+func TestPreviousVersion(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		record      Record
+		expected    string
+		expectErr   bool
+	}{
+		{
+			testComment: "the version installed before the current one",
+			record:      Record{Version: "v3", History: []Record{{Version: "v3"}, {Version: "v2"}, {Version: "v1"}}},
+			expected:    "v2",
+		},
+		{
+			testComment: "no previous version",
+			record:      Record{Version: "v3", History: []Record{{Version: "v3"}}},
+			expectErr:   true,
+		},
+		{
+			testComment: "a second rollback goes further back",
+			record:      Record{Version: "v2", RolledBack: true, History: []Record{{Version: "v3"}, {Version: "v2"}, {Version: "v1"}}},
+			expected:    "v1",
+		},
+		{
+			testComment: "nothing is left after rolling back to the first version",
+			record: Record{Version: "v1", RolledBack: true, History: []Record{
+				{Version: "v2", RolledBack: true}, {Version: "v3"}, {Version: "v2"}, {Version: "v1"}}},
+			expectErr: true,
+		},
+		{
+			testComment: "an upgrade after a rollback returns to the version rolled back to",
+			record:      Record{Version: "v4", History: []Record{{Version: "v2", RolledBack: true}, {Version: "v3"}, {Version: "v2"}}},
+			expected:    "v2",
+		},
+		{
+			testComment: "a rollback to a version never installed is kept",
+			record:      Record{Version: "v0", RolledBack: true, History: []Record{{Version: "v2"}, {Version: "v1"}}},
+			expected:    "v2",
+		},
+	}
+	for _, tc := range td {
+		tc.record.Component = "base"
+		version, err := PreviousVersion(tc.record)
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+		}
+		if version != tc.expected {
+			t.Errorf("%s expected: %q, found: %q", tc.testComment, tc.expected, version)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package inventory
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/ForgeRock/forgeops-cli/internal/printer"
+)
+
+// Print prints the records as a table or as json, depending on the output type
+func Print(records []Record) {
+	if printer.CommandOut == printer.OutJson {
+		printer.JsonDocument("forgeops list", records)
+		return
+	}
+	if len(records) == 0 {
+		printer.Noticef("No components installed by the forgeops-cli were found")
+		return
+	}
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tCOMPONENT\tVERSION\tREPOSITORY\tFQDN\tOBJECTS\tINSTALLED\tCLI VERSION\tCHECKSUM")
+	for _, r := range records {
+		fqdn := r.FQDN
+		if len(fqdn) == 0 {
+			fqdn = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%.12s\n",
+			r.Namespace, r.Component, r.Version, r.GHRepo, fqdn, len(r.Objects),
+			r.InstalledAt.UTC().Format("2006-01-02 15:04:05"), r.CLIVersion, r.Checksum)
+	}
+	w.Flush()
+	printer.Document(buf.String())
+}
@@ -0,0 +1,120 @@
+package profile
+
+var (
+	// DefaultProfile default profile of the Cloud Deployment Quickstart (CDQ)
+	DefaultProfile = []byte(`
+---
+kind: profile
+version: v1alpha1
+metadata:
+  name: cdq
+spec:
+  placeholders:
+    fqdn: default.iam.example.com
+    namespace: default
+  quickstartManifest: quickstart.yaml
+  importantSecrets:
+    - name: am-env-secrets
+      keys:
+        - key: AM_PASSWORDS_AMADMIN_CLEAR
+          description: amadmin user
+    - name: ds-passwords
+      keys:
+        - key: dirmanager.pw
+          description: uid=admin user
+    - name: rcs-agent-env-secrets
+      keys:
+        - key: AGENT_IDM_SECRET
+          description: rcs-agent IDM secret
+        - key: AGENT_RCS_SECRET
+          description: rcs-agent RCS secret
+  components:
+    - name: base
+      manifest: base.yaml
+    - name: directory
+      manifest: ds.yaml
+      aliases: [ds]
+    - name: apps
+      manifest: apps.yaml
+    - name: ui
+      manifest: ui.yaml
+    - name: ds-cts
+      manifest: ds-cts.yaml
+      hidden: true
+    - name: ds-idrepo
+      manifest: ds-idrepo.yaml
+      hidden: true
+    - name: am
+      manifest: am.yaml
+      hidden: true
+    - name: amster
+      manifest: amster.yaml
+      hidden: true
+    - name: idm
+      manifest: idm.yaml
+      hidden: true
+    - name: admin-ui
+      manifest: admin-ui.yaml
+      hidden: true
+    - name: end-user-ui
+      manifest: end-user-ui.yaml
+      aliases: [enduser-ui]
+      hidden: true
+    - name: login-ui
+      manifest: login-ui.yaml
+      hidden: true
+    - name: rcs-agent
+      manifest: rcs-agent.yaml
+      hidden: true
+  tiers:
+    - name: base
+      components: [base]
+      waits:
+        - apiVersion: v1
+          resource: secrets
+          name: am-env-secrets
+          timeout: 30s
+          stage: secrets
+        - apiVersion: v1
+          resource: secrets
+          name: ds-passwords
+          timeout: 30s
+          stage: secrets
+        - apiVersion: v1
+          resource: secrets
+          name: rcs-agent-env-secrets
+          timeout: 30s
+          stage: secrets
+        - apiVersion: apps/v1
+          resource: deployments
+          name: git-server
+          condition: status.availableReplicas>=1
+          timeout: 2m
+    - name: directory
+      components: [directory]
+      waits:
+        - apiVersion: apps/v1
+          resource: statefulsets
+          name: ds-idrepo
+          condition: status.readyReplicas==spec.replicas
+          timeout: 10m
+          stage: ds
+    - name: apps
+      components: [apps]
+      waits:
+        - apiVersion: apps/v1
+          resource: deployments
+          name: am
+          condition: status.availableReplicas>=1
+          timeout: 10m
+        - apiVersion: batch/v1
+          resource: jobs
+          name: amster
+          condition: status.succeeded>=1
+          timeout: 5m
+      # amster only imports the configuration
+      cleanup: [amster]
+    - name: ui
+      components: [ui]
+`)
+)
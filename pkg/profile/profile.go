@@ -0,0 +1,215 @@
+package profile
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// SupportedVersion is the profile version understood by this CLI
+const SupportedVersion = "v1alpha1"
+
+// Placeholders values used in the release manifests that are replaced at install time
+type Placeholders struct {
+	FQDN      string `json:"fqdn"`
+	Namespace string `json:"namespace"`
+}
+
+// SecretKey a key of a secret and the name it's printed with
+type SecretKey struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+}
+
+// Secret a secret generated by the deployment that is relevant to users
+type Secret struct {
+	Name string      `json:"name"`
+	Keys []SecretKey `json:"keys"`
+}
+
+// Component a platform component published as a manifest in the releases
+type Component struct {
+	Name     string   `json:"name"`
+	Manifest string   `json:"manifest"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Hidden   bool     `json:"hidden,omitempty"`
+}
+
+// Wait a condition an object of a tier must meet before the next tier is deployed
+type Wait struct {
+	// APIVersion and Resource identify the type of the object, e.g. apps/v1 and deployments
+	APIVersion string `json:"apiVersion"`
+	Resource   string `json:"resource"`
+	Name       string `json:"name"`
+	// Condition expression evaluated against the object, e.g. status.availableReplicas>=1.
+	// The object only has to exist when empty
+	Condition string          `json:"condition,omitempty"`
+	Timeout   metav1.Duration `json:"timeout"`
+	// Stage name used to override the timeout, e.g. ds. Waits can share a stage. The name of the object is used when empty
+	Stage string `json:"stage,omitempty"`
+}
+
+// StageName returns the stage the timeout of the wait is overridden with
+func (w Wait) StageName() string {
+	if len(w.Stage) > 0 {
+		return w.Stage
+	}
+	return w.Name
+}
+
+// GroupVersionResource returns the type of the object waited for
+func (w Wait) GroupVersionResource() (schema.GroupVersionResource, error) {
+	gv, err := schema.ParseGroupVersion(w.APIVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return gv.WithResource(w.Resource), nil
+}
+
+// Tier a group of components deployed together. Tiers are deployed in order.
+// The waits must be met before the next tier is deployed. The cleanup components are deleted once they're met
+type Tier struct {
+	Name       string   `json:"name"`
+	Components []string `json:"components"`
+	Waits      []Wait   `json:"waits,omitempty"`
+	Cleanup    []string `json:"cleanup,omitempty"`
+}
+
+// V1Alpha1ProfileSpec ProfileSpec
+type V1Alpha1ProfileSpec struct {
+	Placeholders       Placeholders `json:"placeholders"`
+	QuickstartManifest string       `json:"quickstartManifest"`
+	ImportantSecrets   []Secret     `json:"importantSecrets"`
+	Components         []Component  `json:"components"`
+	Tiers              []Tier       `json:"tiers"`
+}
+
+// Profile describes how the platform is deployed
+type Profile struct {
+	Kind     string              `json:"kind"`
+	Version  string              `json:"version"`
+	Metadata metav1.ObjectMeta   `json:"metadata"`
+	Spec     V1Alpha1ProfileSpec `json:"spec"`
+}
+
+// current profile shared by the commands
+var current *Profile
+
+// GetProfileFromBytes deserialize from bytes
+func GetProfileFromBytes(pbytes []byte) (*Profile, error) {
+	p := &Profile{}
+	if err := yaml.UnmarshalStrict(pbytes, p); err != nil {
+		return &Profile{}, err
+	}
+	if err := p.validate(); err != nil {
+		return &Profile{}, err
+	}
+	return p, nil
+}
+
+// Load parses the profile in the given path and makes it the current profile.
+// The default profile is used when no path is provided
+func Load(path string) error {
+	pbytes := DefaultProfile
+	if len(path) > 0 {
+		var err error
+		if pbytes, err = ioutil.ReadFile(path); err != nil {
+			return err
+		}
+	}
+	p, err := GetProfileFromBytes(pbytes)
+	if err != nil {
+		return fmt.Errorf("error loading profile %q: %w", path, err)
+	}
+	current = p
+	return nil
+}
+
+// Current returns the profile loaded by Load or the default profile
+func Current() *Profile {
+	if current == nil {
+		p, err := GetProfileFromBytes(DefaultProfile)
+		if err != nil {
+			panic(err)
+		}
+		current = p
+	}
+	return current
+}
+
+// Component returns the component with the given name or alias
+func (p *Profile) Component(name string) (Component, error) {
+	for _, c := range p.Spec.Components {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	for _, c := range p.Spec.Components {
+		for _, alias := range c.Aliases {
+			if alias == name {
+				return c, nil
+			}
+		}
+	}
+	return Component{}, fmt.Errorf("component %q is not defined in profile %q", name, p.Metadata.Name)
+}
+
+// ManifestComponent returns the component published in the given manifest
+func (p *Profile) ManifestComponent(manifest string) (Component, error) {
+	for _, c := range p.Spec.Components {
+		if c.Manifest == manifest {
+			return c, nil
+		}
+	}
+	return Component{}, fmt.Errorf("manifest %q isn't the manifest of a component of profile %q", manifest, p.Metadata.Name)
+}
+
+// Stages returns the stages of the waits of every tier, in order
+func (p *Profile) Stages() []string {
+	stages := []string{}
+	seen := map[string]bool{}
+	for _, t := range p.Spec.Tiers {
+		for _, w := range t.Waits {
+			if !seen[w.StageName()] {
+				seen[w.StageName()] = true
+				stages = append(stages, w.StageName())
+			}
+		}
+	}
+	return stages
+}
+
+func (p *Profile) validate() error {
+	if p.Version != SupportedVersion {
+		return fmt.Errorf("unsupported profile version %q. Expected %q", p.Version, SupportedVersion)
+	}
+	names := map[string]bool{}
+	for _, c := range p.Spec.Components {
+		if len(c.Name) == 0 || len(c.Manifest) == 0 {
+			return fmt.Errorf("components require a name and a manifest")
+		}
+		names[c.Name] = true
+	}
+	for _, t := range p.Spec.Tiers {
+		for _, c := range append(append([]string{}, t.Components...), t.Cleanup...) {
+			if !names[c] {
+				return fmt.Errorf("tier %q refers to unknown component %q", t.Name, c)
+			}
+		}
+		for _, w := range t.Waits {
+			if len(w.Resource) == 0 || len(w.Name) == 0 {
+				return fmt.Errorf("waits of tier %q require a resource and a name", t.Name)
+			}
+			if _, err := w.GroupVersionResource(); err != nil {
+				return fmt.Errorf("wait for %q in tier %q: %w", w.Name, t.Name, err)
+			}
+			if w.Timeout.Duration <= 0 {
+				return fmt.Errorf("wait for %q in tier %q requires a timeout", w.Name, t.Name)
+			}
+		}
+	}
+	return nil
+}
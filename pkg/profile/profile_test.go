@@ -0,0 +1,211 @@
+package profile
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestGetProfileFromBytes tests profile parsing and validation
+func TestGetProfileFromBytes(t *testing.T) {
+	td := []struct {
+		// comment about test case
+		testComment string
+		// profile contents
+		profile string
+		// expect an error
+		expectErr bool
+	}{
+		{
+			testComment: "default profile is valid",
+			profile:     string(DefaultProfile),
+			expectErr:   false,
+		},
+		{
+			testComment: "unsupported versions are rejected",
+			profile: `
+kind: profile
+version: v2
+metadata:
+  name: test
+`,
+			expectErr: true,
+		},
+		{
+			testComment: "tiers must refer to known components",
+			profile: `
+kind: profile
+version: v1alpha1
+metadata:
+  name: test
+spec:
+  components:
+    - name: base
+      manifest: base.yaml
+  tiers:
+    - name: apps
+      components: [apps]
+`,
+			expectErr: true,
+		},
+		{
+			testComment: "cleanup must refer to known components",
+			profile: `
+kind: profile
+version: v1alpha1
+metadata:
+  name: test
+spec:
+  components:
+    - name: base
+      manifest: base.yaml
+  tiers:
+    - name: base
+      components: [base]
+      cleanup: [amster]
+`,
+			expectErr: true,
+		},
+		{
+			testComment: "waits require a timeout",
+			profile: `
+kind: profile
+version: v1alpha1
+metadata:
+  name: test
+spec:
+  components:
+    - name: base
+      manifest: base.yaml
+  tiers:
+    - name: base
+      components: [base]
+      waits:
+        - apiVersion: apps/v1
+          resource: deployments
+          name: git-server
+          condition: status.availableReplicas>=1
+`,
+			expectErr: true,
+		},
+		{
+			testComment: "waits require a valid apiVersion",
+			profile: `
+kind: profile
+version: v1alpha1
+metadata:
+  name: test
+spec:
+  components:
+    - name: base
+      manifest: base.yaml
+  tiers:
+    - name: base
+      components: [base]
+      waits:
+        - apiVersion: apps/v1/beta
+          resource: deployments
+          name: git-server
+          timeout: 2m
+`,
+			expectErr: true,
+		},
+		{
+			testComment: "unknown fields are rejected",
+			profile: `
+kind: profile
+version: v1alpha1
+metadata:
+  name: test
+spec:
+  component: []
+`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range td {
+		_, err := GetProfileFromBytes([]byte(tc.profile))
+		if (err != nil) != tc.expectErr {
+			t.Errorf("%s expected error: %t, found: %+v", tc.testComment, tc.expectErr, err)
+		}
+	}
+}
+
+// TestComponent tests component lookups
+func TestComponent(t *testing.T) {
+	p := Current()
+	component, err := p.Component("directory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if component.Manifest != "ds.yaml" {
+		t.Errorf("expected ds.yaml, found: %s", component.Manifest)
+	}
+	if _, err := p.Component("missing"); err == nil {
+		t.Error("expected an error for an unknown component")
+	}
+}
+
+// TestTierWaits tests the waits of the default profile are read as data
+func TestTierWaits(t *testing.T) {
+	p := Current()
+	var apps Tier
+	for _, tier := range p.Spec.Tiers {
+		if tier.Name == "apps" {
+			apps = tier
+		}
+	}
+	if len(apps.Waits) != 2 {
+		t.Fatalf("expected 2 waits in the apps tier, found: %d", len(apps.Waits))
+	}
+	gvr, err := apps.Waits[1].GroupVersionResource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gvr.Group != "batch" || gvr.Version != "v1" || gvr.Resource != "jobs" {
+		t.Errorf("expected batch/v1 jobs, found: %s", gvr)
+	}
+	if apps.Waits[1].Timeout.Duration.Minutes() != 5 {
+		t.Errorf("expected a 5m timeout, found: %s", apps.Waits[1].Timeout.Duration)
+	}
+	if len(apps.Cleanup) != 1 || apps.Cleanup[0] != "amster" {
+		t.Errorf("expected amster to be cleaned up, found: %v", apps.Cleanup)
+	}
+	stages := []string{"secrets", "git-server", "ds", "am", "amster"}
+	if !reflect.DeepEqual(p.Stages(), stages) {
+		t.Errorf("expected the stages %v, found: %v", stages, p.Stages())
+	}
+}
+
+// TestManifestComponent tests components are found by the manifest they're published in
+func TestManifestComponent(t *testing.T) {
+	p := Current()
+	c, err := p.ManifestComponent("ds.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "directory" {
+		t.Errorf("expected the directory component, found: %q", c.Name)
+	}
+	if _, err := p.ManifestComponent("secret-agent.yaml"); err == nil {
+		t.Error("expected manifests outside of the profile not to be found")
+	}
+}
+
+// TestComponentAlias tests components are found by name or alias
+func TestComponentAlias(t *testing.T) {
+	p := Current()
+	for _, name := range []string{"directory", "ds"} {
+		c, err := p.Component(name)
+		if err != nil {
+			t.Errorf("%s expected to be found, found: %+v", name, err)
+			continue
+		}
+		if c.Name != "directory" {
+			t.Errorf("%s expected the directory component, found: %q", name, c.Name)
+		}
+	}
+	if _, err := p.Component("secret-agent"); err == nil {
+		t.Error("expected components outside of the profile not to be found")
+	}
+}
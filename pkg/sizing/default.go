@@ -0,0 +1,118 @@
+package sizing
+
+var (
+	// DefaultSizes sizes of the Cloud Deployment Quickstart (CDQ).
+	// cdk leaves the release manifests untouched
+	DefaultSizes = []byte(`
+---
+kind: sizes
+version: v1alpha1
+metadata:
+  name: cdq
+spec:
+  sizes:
+    - name: cdk
+      description: Cloud Developer's Kit. The sizes of the release manifests
+      workloads: []
+    - name: mini
+      description: Single replicas with minimal resources for local clusters, e.g. minikube
+      workloads:
+        - name: am
+          replicas: 1
+          resources:
+            requests: {cpu: 250m, memory: 1Gi}
+            limits: {memory: 1536Mi}
+        - name: idm
+          replicas: 1
+          resources:
+            requests: {cpu: 250m, memory: 1Gi}
+            limits: {memory: 1536Mi}
+        - name: ds-idrepo
+          replicas: 1
+          resources:
+            requests: {cpu: 250m, memory: 1Gi}
+            limits: {memory: 1536Mi}
+          storage: 10Gi
+        - name: ds-cts
+          replicas: 1
+          resources:
+            requests: {cpu: 250m, memory: 1Gi}
+            limits: {memory: 1536Mi}
+          storage: 10Gi
+    - name: small
+      description: Small production deployment
+      workloads:
+        - name: am
+          replicas: 2
+          resources:
+            requests: {cpu: "2", memory: 4Gi}
+            limits: {memory: 4Gi}
+        - name: idm
+          replicas: 2
+          resources:
+            requests: {cpu: "1", memory: 2Gi}
+            limits: {memory: 2Gi}
+        - name: ds-idrepo
+          replicas: 3
+          resources:
+            requests: {cpu: "2", memory: 4Gi}
+            limits: {memory: 6Gi}
+          storage: 100Gi
+        - name: ds-cts
+          replicas: 3
+          resources:
+            requests: {cpu: "2", memory: 4Gi}
+            limits: {memory: 6Gi}
+          storage: 100Gi
+    - name: medium
+      description: Medium production deployment
+      workloads:
+        - name: am
+          replicas: 3
+          resources:
+            requests: {cpu: "6", memory: 8Gi}
+            limits: {memory: 8Gi}
+        - name: idm
+          replicas: 2
+          resources:
+            requests: {cpu: "2", memory: 4Gi}
+            limits: {memory: 4Gi}
+        - name: ds-idrepo
+          replicas: 3
+          resources:
+            requests: {cpu: "6", memory: 11Gi}
+            limits: {memory: 14Gi}
+          storage: 850Gi
+        - name: ds-cts
+          replicas: 3
+          resources:
+            requests: {cpu: "6", memory: 11Gi}
+            limits: {memory: 14Gi}
+          storage: 350Gi
+    - name: large
+      description: Large production deployment
+      workloads:
+        - name: am
+          replicas: 3
+          resources:
+            requests: {cpu: "12", memory: 20Gi}
+            limits: {memory: 26Gi}
+        - name: idm
+          replicas: 2
+          resources:
+            requests: {cpu: "8", memory: 8Gi}
+            limits: {memory: 8Gi}
+        - name: ds-idrepo
+          replicas: 3
+          resources:
+            requests: {cpu: "12", memory: 21Gi}
+            limits: {memory: 29Gi}
+          storage: 1000Gi
+        - name: ds-cts
+          replicas: 3
+          resources:
+            requests: {cpu: "12", memory: 21Gi}
+            limits: {memory: 29Gi}
+          storage: 1000Gi
+`)
+)
@@ -0,0 +1,148 @@
+package sizing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// SupportedVersion is the sizes version understood by this CLI
+const SupportedVersion = "v1alpha1"
+
+// Workload size of a workload of the platform
+type Workload struct {
+	// Name of the Deployment, StatefulSet or DirectoryService
+	Name string `json:"name"`
+	// Container the resources apply to. The resources apply to every container when empty
+	Container string                       `json:"container,omitempty"`
+	Replicas  *int32                       `json:"replicas,omitempty"`
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Storage size of the persistent volume claim, e.g. the DS data volume
+	Storage *resource.Quantity `json:"storage,omitempty"`
+	// VolumeClaim name of the volume claim template of a StatefulSet the storage applies to. Defaults to data
+	VolumeClaim string `json:"volumeClaim,omitempty"`
+}
+
+// DefaultVolumeClaim volume claim template the storage of StatefulSets applies to by default
+const DefaultVolumeClaim = "data"
+
+// Claim returns the name of the volume claim template the storage applies to
+func (w Workload) Claim() string {
+	if len(w.VolumeClaim) == 0 {
+		return DefaultVolumeClaim
+	}
+	return w.VolumeClaim
+}
+
+// Size a named set of workload sizes, e.g. small
+type Size struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Workloads   []Workload `json:"workloads"`
+}
+
+// V1Alpha1SizesSpec SizesSpec
+type V1Alpha1SizesSpec struct {
+	Sizes []Size `json:"sizes"`
+}
+
+// Sizes describes the sizes the platform can be installed with
+type Sizes struct {
+	Kind     string            `json:"kind"`
+	Version  string            `json:"version"`
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     V1Alpha1SizesSpec `json:"spec"`
+}
+
+// GetSizesFromBytes deserialize from bytes
+func GetSizesFromBytes(sbytes []byte) (*Sizes, error) {
+	s := &Sizes{}
+	if err := yaml.UnmarshalStrict(sbytes, s); err != nil {
+		return &Sizes{}, err
+	}
+	if err := s.validate(); err != nil {
+		return &Sizes{}, err
+	}
+	return s, nil
+}
+
+// Load returns the default sizes extended with the sizes in the given path.
+// Sizes in the path replace the default sizes with the same name
+func Load(path string) (*Sizes, error) {
+	sizes, err := GetSizesFromBytes(DefaultSizes)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return sizes, nil
+	}
+	sbytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := GetSizesFromBytes(sbytes)
+	if err != nil {
+		return nil, fmt.Errorf("error loading sizes %q: %w", path, err)
+	}
+	for _, size := range custom.Spec.Sizes {
+		sizes.set(size)
+	}
+	return sizes, nil
+}
+
+// Size returns the size with the given name
+func (s *Sizes) Size(name string) (Size, error) {
+	for _, size := range s.Spec.Sizes {
+		if size.Name == name {
+			return size, nil
+		}
+	}
+	names := []string{}
+	for _, size := range s.Spec.Sizes {
+		names = append(names, size.Name)
+	}
+	sort.Strings(names)
+	return Size{}, fmt.Errorf("unknown size %q. Available sizes: %v", name, names)
+}
+
+// Workload returns the size of the workload with the given name
+func (s Size) Workload(name string) (Workload, bool) {
+	for _, w := range s.Workloads {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return Workload{}, false
+}
+
+func (s *Sizes) set(size Size) {
+	for i := range s.Spec.Sizes {
+		if s.Spec.Sizes[i].Name == size.Name {
+			s.Spec.Sizes[i] = size
+			return
+		}
+	}
+	s.Spec.Sizes = append(s.Spec.Sizes, size)
+}
+
+func (s *Sizes) validate() error {
+	if s.Version != SupportedVersion {
+		return fmt.Errorf("unsupported sizes version %q. Expected %q", s.Version, SupportedVersion)
+	}
+	for _, size := range s.Spec.Sizes {
+		if len(size.Name) == 0 {
+			return fmt.Errorf("sizes require a name")
+		}
+		for _, w := range size.Workloads {
+			if len(w.Name) == 0 {
+				return fmt.Errorf("the workloads of size %q require a name", size.Name)
+			}
+		}
+	}
+	return nil
+}
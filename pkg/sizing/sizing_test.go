@@ -0,0 +1,86 @@
+package sizing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultSizes(t *testing.T) {
+	sizes, err := GetSizesFromBytes(DefaultSizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"cdk", "mini", "small", "medium", "large"} {
+		if _, err := sizes.Size(name); err != nil {
+			t.Error(err)
+		}
+	}
+	small, _ := sizes.Size("small")
+	ds, found := small.Workload("ds-idrepo")
+	if !found {
+		t.Fatal("expected the ds-idrepo size in small")
+	}
+	if ds.Storage == nil || ds.Storage.String() != "100Gi" {
+		t.Errorf("expected 100Gi of storage, found %v", ds.Storage)
+	}
+	if ds.Claim() != "data" {
+		t.Errorf("expected the storage of the data claim, found %q", ds.Claim())
+	}
+	if _, err := sizes.Size("huge"); err == nil {
+		t.Error("expected an error for an unknown size")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sizes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sizes.yaml")
+	custom := []byte(`
+kind: sizes
+version: v1alpha1
+metadata:
+  name: prod
+spec:
+  sizes:
+    - name: small
+      workloads:
+        - name: am
+          replicas: 4
+    - name: prod
+      workloads:
+        - name: am
+          replicas: 6
+`)
+	if err := ioutil.WriteFile(path, custom, 0600); err != nil {
+		t.Fatal(err)
+	}
+	sizes, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, _ := sizes.Size("small")
+	if am, _ := small.Workload("am"); am.Replicas == nil || *am.Replicas != 4 {
+		t.Errorf("expected the custom small size to replace the default one")
+	}
+	if _, found := small.Workload("ds-idrepo"); found {
+		t.Errorf("expected the custom small size to replace the default one entirely")
+	}
+	if _, err := sizes.Size("prod"); err != nil {
+		t.Error(err)
+	}
+	if _, err := sizes.Size("medium"); err != nil {
+		t.Errorf("expected the default sizes to be kept: %s", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("kind: sizes\nversion: v2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected an error for an unsupported version")
+	}
+}